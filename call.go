@@ -0,0 +1,161 @@
+package telebbb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Params Generic params for Call, keys are telegram param names
+type Params map[string]interface{}
+
+// formFile File sent as multipart/form-data part with param name
+type formFile struct {
+	name string
+	file *InputFile
+}
+
+// Call Calls any telegram method by it's name, useful for methods which are not wrapped by library yet
+// params can be any struct with json tags, Params map or nil for methods without params. If params contain *os.File
+// or uploaded *InputFile values request is sent as multipart/form-data with those files and *os.File values are closed
+// after request, otherwise it's sent as JSON.
+// Telegram result is decoded into result, pass nil if result is not needed. Unsuccessful requests return *APIError
+func (t *TbBot) Call(method string, params interface{}, result interface{}) error {
+	return t.CallContext(context.Background(), method, params, result)
+}
+
+// CallContext Same as Call, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) CallContext(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if method == "" {
+		return fmt.Errorf("method name can't be empty")
+	}
+	var resp []byte
+	var e error
+	switch files := filesOf(params); {
+	case params == nil:
+		resp, e = t.sendGet(ctx, method)
+	case len(files) > 0:
+		resp, e = t.uploadFiles(ctx, method, params, files)
+	default:
+		resp, e = t.sendPost(ctx, params, method)
+	}
+	if e != nil {
+		return e
+	}
+	return decodeResult(resp, result)
+}
+
+// callFile Same as CallContext, but file param is sent with sendFile, so it's cached and taken from message like in SendPhoto
+func (t *TbBot) callFile(ctx context.Context, method string, name string, params interface{}, result interface{}) error {
+	if params == nil {
+		return t.CallContext(ctx, method, params, result)
+	}
+	resp, e := t.sendFile(ctx, method, name, params, nil)
+	if e != nil {
+		return e
+	}
+	return decodeResult(resp, result)
+}
+
+// decodeResult Decodes result field of telegram responce into result, nil result is skipped
+func decodeResult(resp []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	var r struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if e := json.Unmarshal(resp, &r); e != nil {
+		return e
+	}
+	return json.Unmarshal(r.Result, result)
+}
+
+// filesOf Finds *os.File and uploaded *InputFile values in params top level fields and returns them keyed by param name
+func filesOf(params interface{}) (files []formFile) {
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if f := inputFileOf(iter.Value().Interface()); f != nil {
+				files = append(files, formFile{name: iter.Key().String(), file: f})
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if sf.PkgPath != "" || sf.Tag.Get("json") == "-" { // unexported or skipped field
+				continue
+			}
+			if f := inputFileOf(v.Field(i).Interface()); f != nil {
+				files = append(files, formFile{name: jsonName(sf), file: f})
+			}
+		}
+	}
+	return files
+}
+
+// paramOf Returns value of params top level field by it's param name, nil if there is no such field
+func paramOf(params interface{}, name string) interface{} {
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		if p := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); p.IsValid() {
+			return p.Interface()
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if sf.PkgPath == "" && jsonName(sf) == name {
+				return v.Field(i).Interface()
+			}
+		}
+	}
+	return nil
+}
+
+// inputFileOf Returns file which has to be uploaded if v is *os.File or uploaded *InputFile
+func inputFileOf(v interface{}) *InputFile {
+	switch f := v.(type) {
+	case *os.File:
+		if f != nil {
+			return FileReader(f.Name(), f)
+		}
+	case *InputFile:
+		if f != nil && f.upload() {
+			return f
+		}
+	}
+	return nil
+}
+
+// jsonName Returns param name of struct field from it's json tag
+func jsonName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
@@ -0,0 +1,97 @@
+package telebbb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Answer Answers callback query of update with notification text, alert shows it as alert instead of notification
+// Callback queries not answered by handler are answered by dispatcher without text. Handlers which answer query with
+// Bot.AnswerCallbackQueryContext have to pass Context as ctx, otherwise dispatcher answers query second time
+func (c *Context) Answer(text string, alert bool) error {
+	return c.AnswerCallback(AnswerCallbackQueryType{Text: text, ShowAlert: alert})
+}
+
+// AnswerCallback Answers callback query of update with answer, callback_query_id is set automatically
+func (c *Context) AnswerCallback(answer AnswerCallbackQueryType) error {
+	if c.Callback == nil {
+		return fmt.Errorf("update has no callback query to answer")
+	}
+	if c.answered {
+		return fmt.Errorf("callback query %s is already answered", c.Callback.ID)
+	}
+	answer.CallbackQuery = c.Callback.ID
+	c.answered = true
+	_, e := c.Bot.AnswerCallbackQueryContext(c, answer)
+	return e
+}
+
+// markAnswered Remembers that callback query of update was answered with message params
+func (c *Context) markAnswered(message interface{}) {
+	if c.Callback != nil && fmt.Sprint(paramOf(message, "callback_query_id")) == c.Callback.ID {
+		c.answered = true
+	}
+}
+
+// Callback Registers handler for callback queries which data starts with prefix, data after prefix is set to Context.Args
+func (d *Dispatcher) Callback(prefix string, h HandlerFunc) {
+	d.Handle(func(c *Context) bool {
+		if c.Callback == nil || !strings.HasPrefix(c.Callback.Data, prefix) {
+			return false
+		}
+		c.Args = strings.TrimPrefix(c.Callback.Data, prefix)
+		return true
+	}, h)
+}
+
+// CallbackPattern Registers handler for callback queries which data matches pattern, {name} parts of pattern are set to Context.Params
+// For example pattern "vote:{poll}:{choice}" matches data "vote:12:yes" with params poll=12 and choice=yes.
+// Param matches any text without ':', pattern ending with '*' matches any data which starts with pattern
+func (d *Dispatcher) CallbackPattern(pattern string, h HandlerFunc) {
+	d.CallbackRegexp(patternRegexp(pattern), h)
+}
+
+// CallbackRegexp Registers handler for callback queries which data matches re
+// Submatches are set to Context.Matches and named submatches to Context.Params
+func (d *Dispatcher) CallbackRegexp(re *regexp.Regexp, h HandlerFunc) {
+	d.Handle(func(c *Context) bool {
+		if c.Callback == nil {
+			return false
+		}
+		m := re.FindStringSubmatch(c.Callback.Data)
+		if m == nil {
+			return false
+		}
+		c.Matches = m
+		c.Params = make(map[string]string)
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				c.Params[name] = m[i]
+			}
+		}
+		return true
+	}, h)
+}
+
+// patternParam Matches {name} params of callback pattern
+var patternParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// patternRegexp Converts callback pattern to regexp
+func patternRegexp(pattern string) *regexp.Regexp {
+	prefix := strings.HasSuffix(pattern, "*")
+	pattern = strings.TrimSuffix(pattern, "*")
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range patternParam.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		b.WriteString("(?P<" + pattern[loc[2]:loc[3]] + ">[^:]*)")
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	if !prefix {
+		b.WriteString("$")
+	}
+	return regexp.MustCompile(b.String())
+}
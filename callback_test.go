@@ -0,0 +1,57 @@
+package telebbb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCallbackAnsweredByBot Checks that query answered with Bot.AnswerCallbackQuery is not answered by dispatcher again
+// or it's rejected answer is not reported as handler error
+func TestCallbackAnsweredByBot(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func(c *Context) context.Context // ctx passed to AnswerCallbackQueryContext by handler
+		answers int                              // answerCallbackQuery requests expected
+	}{
+		{"handler context", func(c *Context) context.Context { return c }, 1},
+		{"other context", func(c *Context) context.Context { return context.Background() }, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/answerCallbackQuery") {
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+				answers++
+				if answers > 1 {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: query is too old and response timeout expired or query ID is invalid"}`))
+					return
+				}
+				w.Write([]byte(`{"ok":true,"result":true}`))
+			}))
+			defer srv.Close()
+
+			b, e := NewBot(BotConfig{Type: "none", Token: "test", APIURL: srv.URL})
+			if e != nil {
+				t.Fatal(e)
+			}
+			d := NewDispatcher(b)
+			d.Callback("vote:", func(c *Context) error {
+				_, e := c.Bot.AnswerCallbackQueryContext(tt.ctx(c), AnswerCallbackQueryType{CallbackQuery: c.Callback.ID, Text: "Thanks"})
+				return e
+			})
+			u := &Update{CallbackQuery: &CallbackQuery{ID: "42", Data: "vote:yes", From: &User{ID: 1}}}
+			if e = d.HandleUpdate(context.Background(), u); e != nil {
+				t.Fatal(e)
+			}
+			if answers != tt.answers {
+				t.Errorf("query was answered %d times, want %d", answers, tt.answers)
+			}
+		})
+	}
+}
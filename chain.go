@@ -0,0 +1,135 @@
+package telebbb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware Wraps update handling, middleware can skip update by not calling next, change Context or handle
+// handler error. Middleware registered with Dispatcher.Use wraps routing too, so it sees every update
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// PanicError Panic recovered by Recover middleware
+type PanicError struct {
+	Value interface{} // Value - value passed to panic
+	Stack []byte      // Stack - stack trace of goroutine where panic happened
+}
+
+// Error Returns error message
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic while handling update: %v", p.Value)
+}
+
+// Use Adds middleware to dispatcher chain, first added middleware is the outermost one
+func (d *Dispatcher) Use(mw ...Middleware) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.middleware = append(d.middleware, mw...)
+}
+
+// OnError Sets handler of errors returned by handlers or middleware, by default errors are sent to bot Errors channel
+func (d *Dispatcher) OnError(h func(u *Update, e error)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onError = h
+}
+
+// handleError Passes error to error handler or to bot Errors channel
+func (d *Dispatcher) handleError(u *Update, e error) {
+	d.mu.RLock()
+	h := d.onError
+	d.mu.RUnlock()
+	if h != nil {
+		h(u, e)
+		return
+	}
+	d.bot.reportError(e)
+}
+
+// chain Wraps h with middleware
+func chain(h HandlerFunc, mw []Middleware) HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// Recover Middleware that recovers panic of handler and returns it as *PanicError
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) (e error) {
+			defer func() {
+				if v := recover(); v != nil {
+					e = &PanicError{Value: v, Stack: debug.Stack()}
+				}
+			}()
+			return next(c)
+		}
+	}
+}
+
+// Timeout Middleware that sets deadline of update handling, api calls made with Context are canceled after it
+func Timeout(d time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			parent := c.Context
+			ctx, cancel := context.WithTimeout(parent, d)
+			defer func() {
+				cancel()
+				c.Context = parent
+			}()
+			c.Context = ctx
+			return next(c)
+		}
+	}
+}
+
+// Timing Middleware that reports how long update was handled and handler error, handy for metrics
+func Timing(observe func(c *Context, d time.Duration, e error)) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			start := time.Now()
+			e := next(c)
+			observe(c, time.Since(start), e)
+			return e
+		}
+	}
+}
+
+// Logger Middleware that logs every update with it's handling time and error
+func Logger(l *log.Logger) Middleware {
+	return Timing(func(c *Context, d time.Duration, e error) {
+		if e != nil {
+			l.Printf("update %d failed in %s: %v", c.Update.UpdateID, d, e)
+		} else {
+			l.Printf("update %d handled in %s", c.Update.UpdateID, d)
+		}
+	})
+}
+
+// Filter Middleware that passes to handlers only updates allowed by allow, other updates are ignored
+func Filter(allow func(c *Context) bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if !allow(c) {
+				return nil
+			}
+			return next(c)
+		}
+	}
+}
+
+// AllowUsers Middleware that passes to handlers only updates sent by users with ids
+func AllowUsers(ids ...int) Middleware {
+	allowed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		allowed[id] = true
+	}
+	return Filter(func(c *Context) bool {
+		u := c.Sender()
+		return u != nil && allowed[u.ID]
+	})
+}
@@ -0,0 +1,312 @@
+package telebbb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf16"
+)
+
+// Media types of message used with Dispatcher.Media, they are named as message fields in telegram api
+const (
+	MediaPhoto     = "photo"
+	MediaAudio     = "audio"
+	MediaDocument  = "document"
+	MediaVideo     = "video"
+	MediaAnimation = "animation"
+	MediaVoice     = "voice"
+	MediaVideoNote = "video_note"
+	MediaSticker   = "sticker"
+	MediaContact   = "contact"
+	MediaLocation  = "location"
+	MediaVenue     = "venue"
+	MediaDice      = "dice"
+)
+
+// HandlerFunc Handles update, returned error is passed to dispatcher error handler
+type HandlerFunc func(c *Context) error
+
+// Context Update passed to handler, it's canceled when dispatcher stops
+type Context struct {
+	context.Context
+	Bot     *TbBot   // Bot - bot which received update
+	Update  *Update  // Update - update being handled
+	Message *Message // Message - new message or channel post of update, nil for other updates
+	Command string   // Command - command name without slash and bot username, for example "start" for "/start@my_bot"
+	Args    string   // Args - text after command
+	Matches []string // Matches - submatches of regexp handler
+
+	Callback *CallbackQuery    // Callback - callback query of update, nil for other updates
+	Params   map[string]string // Params - params extracted from callback data by pattern handler
+
+	answered bool        // callback query was answered by handler
+	session  *sessionRef // conversation session of update, set by Conversation middleware
+}
+
+// Text Returns message text or caption of media message
+func (c *Context) Text() string {
+	if c.Message == nil {
+		return ""
+	}
+	if c.Message.Text != "" {
+		return c.Message.Text
+	}
+	return c.Message.Caption
+}
+
+// Chat Returns chat update came from, nil if update has no chat
+func (c *Context) Chat() *Chat {
+	return c.Update.Chat()
+}
+
+// Sender Returns user who caused update, nil if update has no user
+func (c *Context) Sender() *User {
+	return c.Update.Sender()
+}
+
+// Reply Sends text message to chat update came from
+func (c *Context) Reply(text string) (*Message, error) {
+	chat := c.Chat()
+	if chat == nil {
+		return nil, fmt.Errorf("update has no chat to reply to")
+	}
+	return c.Bot.SendMessageContext(c, SendMessageType{ChatID: chat.ID, Text: text})
+}
+
+// route Handler with condition update has to match
+type route struct {
+	match   func(c *Context) bool
+	handler HandlerFunc
+}
+
+// Dispatcher Routes updates from bot to handlers, each update is handled by first matching handler in order of registration
+type Dispatcher struct {
+	bot        *TbBot
+	mu         sync.RWMutex
+	routes     []route
+	middleware []Middleware
+	onError    func(u *Update, e error)
+	nameMu     sync.Mutex
+	name       string // bot username, requested with getMe when it's needed first time
+}
+
+// NewDispatcher Creates dispatcher for updates of bot
+func NewDispatcher(bot *TbBot) *Dispatcher {
+	return &Dispatcher{bot: bot}
+}
+
+// Handle Registers handler for updates matching to match
+func (d *Dispatcher) Handle(match func(c *Context) bool, h HandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.routes = append(d.routes, route{match: match, handler: h})
+}
+
+// Command Registers handler for /name command, commands sent to other bots as /name@other_bot are ignored
+func (d *Dispatcher) Command(name string, h HandlerFunc) {
+	name = strings.TrimPrefix(name, "/")
+	d.Handle(func(c *Context) bool {
+		return c.Command != "" && strings.EqualFold(c.Command, name)
+	}, h)
+}
+
+// Text Registers handler for messages with exactly this text
+func (d *Dispatcher) Text(text string, h HandlerFunc) {
+	d.Handle(func(c *Context) bool {
+		return c.Message != nil && c.Message.Text == text
+	}, h)
+}
+
+// Regexp Registers handler for messages which text or caption matches re, submatches are set to Context.Matches
+func (d *Dispatcher) Regexp(re *regexp.Regexp, h HandlerFunc) {
+	d.Handle(func(c *Context) bool {
+		if c.Message == nil {
+			return false
+		}
+		m := re.FindStringSubmatch(c.Text())
+		if m == nil {
+			return false
+		}
+		c.Matches = m
+		return true
+	}, h)
+}
+
+// Media Registers handler for messages with media of kind, for example MediaPhoto
+func (d *Dispatcher) Media(kind string, h HandlerFunc) {
+	d.Handle(func(c *Context) bool {
+		return c.Message != nil && hasMedia(c.Message, kind)
+	}, h)
+}
+
+// Message Registers handler for any message, register it last as it matches all messages
+func (d *Dispatcher) Message(h HandlerFunc) {
+	d.Handle(func(c *Context) bool {
+		return c.Message != nil
+	}, h)
+}
+
+// Run Handles updates from bot Incoming channel until ctx is done or channel is closed
+// Handler errors are passed to OnError handler or reported to bot Errors channel
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case u, ok := <-d.bot.Incoming:
+			if !ok {
+				return nil
+			}
+			if e := d.HandleUpdate(ctx, u); e != nil {
+				d.handleError(u, e)
+			}
+		}
+	}
+}
+
+// HandleUpdate Passes update through middleware to first matching handler and returns it's error, update without matching handler is ignored
+// Callback queries not answered by handler are answered with empty answer
+func (d *Dispatcher) HandleUpdate(ctx context.Context, u *Update) error {
+	c, e := d.newContext(ctx, u)
+	if e != nil {
+		return e
+	}
+	d.mu.RLock()
+	routes, mw := d.routes, d.middleware
+	d.mu.RUnlock()
+	e = chain(func(c *Context) error {
+		for _, r := range routes {
+			if r.match(c) {
+				return r.handler(c)
+			}
+		}
+		return nil
+	}, mw)(c)
+	// Callback query is answered even if handler failed, wasn't found or update was filtered, so client stops showing loading spinner
+	// Query answered in other way, for example with Bot.AnswerCallbackQuery without Context, is rejected as too old and it's ignored
+	if c.Callback != nil && !c.answered {
+		if ae := c.Answer("", false); ae != nil && !errors.Is(ae, ErrQueryTooOld) && e == nil {
+			e = ae
+		}
+	}
+	return e
+}
+
+// newContext Creates handler context for update and parses message command
+func (d *Dispatcher) newContext(ctx context.Context, u *Update) (*Context, error) {
+	c := &Context{Context: ctx, Bot: d.bot, Update: u, Callback: u.CallbackQuery}
+	switch {
+	case u.Message != nil:
+		c.Message = u.Message
+	case u.ChannelPost != nil:
+		c.Message = u.ChannelPost
+	}
+	if c.Message == nil {
+		return c, nil
+	}
+	cmd, bot, args, ok := parseCommand(c.Message)
+	if !ok {
+		return c, nil
+	}
+	if bot != "" {
+		name, e := d.username(ctx)
+		if e != nil {
+			return nil, e
+		}
+		if !strings.EqualFold(bot, name) { // command for other bot in group
+			return c, nil
+		}
+	}
+	c.Command, c.Args = cmd, args
+	return c, nil
+}
+
+// username Returns bot username, it's requested with getMe only once
+func (d *Dispatcher) username(ctx context.Context) (string, error) {
+	d.nameMu.Lock()
+	defer d.nameMu.Unlock()
+	if d.name != "" {
+		return d.name, nil
+	}
+	me, e := d.bot.GetMeContext(ctx)
+	if e != nil {
+		return "", e
+	}
+	d.name = me.UserName
+	return d.name, nil
+}
+
+// parseCommand Returns command name, bot username and arguments of message which starts with bot_command entity
+// Messages without entities are parsed by leading slash
+func parseCommand(m *Message) (cmd string, bot string, args string, ok bool) {
+	text, entities := m.Text, m.Entities
+	if text == "" {
+		text, entities = m.Caption, m.CaptionEntities
+	}
+	if !strings.HasPrefix(text, "/") {
+		return "", "", "", false
+	}
+	var token string
+	if len(entities) > 0 {
+		for _, e := range entities {
+			if e != nil && e.Type == "bot_command" && e.Offset == 0 {
+				token = entityText(text, e)
+				break
+			}
+		}
+		if token == "" {
+			return "", "", "", false
+		}
+	} else {
+		token = strings.Fields(text)[0]
+	}
+	args = strings.TrimSpace(strings.TrimPrefix(text, token))
+	cmd = strings.TrimPrefix(token, "/")
+	if i := strings.Index(cmd, "@"); i >= 0 {
+		cmd, bot = cmd[:i], cmd[i+1:]
+	}
+	return cmd, bot, args, cmd != ""
+}
+
+// entityText Returns part of text marked by entity, entity offsets are in UTF-16 code units
+func entityText(text string, e *MessageEntity) string {
+	u := utf16.Encode([]rune(text))
+	if e.Offset < 0 || e.Length < 0 || e.Offset+e.Length > len(u) {
+		return ""
+	}
+	return string(utf16.Decode(u[e.Offset : e.Offset+e.Length]))
+}
+
+// hasMedia Reports if message has media of kind
+func hasMedia(m *Message, kind string) bool {
+	switch kind {
+	case MediaPhoto:
+		return len(m.Photo) > 0
+	case MediaAudio:
+		return m.Audio != nil
+	case MediaDocument:
+		return m.Document != nil
+	case MediaVideo:
+		return m.Video != nil
+	case MediaAnimation:
+		return m.Animation != nil
+	case MediaVoice:
+		return m.Voice != nil
+	case MediaVideoNote:
+		return m.VideoNote != nil
+	case MediaSticker:
+		return m.Sticker != nil
+	case MediaContact:
+		return m.Contact != nil
+	case MediaLocation:
+		return m.Location != nil
+	case MediaVenue:
+		return m.Venue != nil
+	case MediaDice:
+		return m.Dice != nil
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package telebbb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrFileSize Downloaded content size differs from File.FileSize returned by GetFile
+var ErrFileSize = errors.New("downloaded file size doesn't match file_size")
+
+// OpenFile Gets file info by file_id with GetFile and opens file content for reading, reader must be closed by caller
+// Reader returns ErrFileSize if content is longer or shorter than File.FileSize
+func (t *TbBot) OpenFile(fileID string) (io.ReadCloser, *File, error) {
+	return t.OpenFileContext(context.Background(), fileID)
+}
+
+// OpenFileContext Same as OpenFile, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) OpenFileContext(ctx context.Context, fileID string) (io.ReadCloser, *File, error) {
+	if fileID == "" {
+		return nil, nil, fmt.Errorf("file id can't be empty")
+	}
+	f, e := t.GetFileContext(ctx, GetFileType{FileID: fileID})
+	if e != nil {
+		return nil, nil, e
+	}
+	link, e := t.FileLink(f)
+	if e != nil {
+		return nil, f, e
+	}
+	var body io.ReadCloser
+	if strings.HasPrefix(link, "file://") {
+		// Local Bot API server stores files on the same machine
+		if body, e = os.Open(f.FilePath); e != nil {
+			return nil, f, e
+		}
+	} else {
+		req, e := http.NewRequest("GET", link, nil)
+		if e != nil {
+			return nil, f, e
+		}
+		resp, e := t.client.Do(req.WithContext(ctx))
+		if e != nil {
+			return nil, f, e
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, f, fmt.Errorf("can't download file %s, we got invalid status code %d", f.FilePath, resp.StatusCode)
+		}
+		if resp.ContentLength >= 0 && f.FileSize > 0 && resp.ContentLength != int64(f.FileSize) {
+			resp.Body.Close()
+			return nil, f, ErrFileSize
+		}
+		body = resp.Body
+	}
+	return &sizeReader{ReadCloser: body, size: int64(f.FileSize)}, f, nil
+}
+
+// DownloadFile Writes content of file with file_id to w, returns file info from GetFile
+func (t *TbBot) DownloadFile(fileID string, w io.Writer) (*File, error) {
+	return t.DownloadFileContext(context.Background(), fileID, w)
+}
+
+// DownloadFileContext Same as DownloadFile, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) DownloadFileContext(ctx context.Context, fileID string, w io.Writer) (*File, error) {
+	r, f, e := t.OpenFileContext(ctx, fileID)
+	if e != nil {
+		return f, e
+	}
+	defer r.Close()
+	_, e = io.Copy(w, r)
+	return f, e
+}
+
+// DownloadFileTo Saves file with file_id to local path, file is written to temporary file first so path never has partial content
+func (t *TbBot) DownloadFileTo(fileID string, path string) (*File, error) {
+	return t.DownloadFileToContext(context.Background(), fileID, path)
+}
+
+// DownloadFileToContext Same as DownloadFileTo, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) DownloadFileToContext(ctx context.Context, fileID string, path string) (*File, error) {
+	tmp, e := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.part")
+	if e != nil {
+		return nil, e
+	}
+	f, e := t.DownloadFileContext(ctx, fileID, tmp)
+	if ce := tmp.Close(); e == nil {
+		e = ce
+	}
+	if e == nil {
+		e = os.Rename(tmp.Name(), path)
+	}
+	if e != nil {
+		os.Remove(tmp.Name())
+		return f, e
+	}
+	return f, nil
+}
+
+// sizeReader Checks that content has expected size, size 0 means it's unknown
+type sizeReader struct {
+	io.ReadCloser
+	size int64
+	read int64
+}
+
+// Read Reads content and returns ErrFileSize if it's longer or shorter than expected
+func (r *sizeReader) Read(p []byte) (int, error) {
+	n, e := r.ReadCloser.Read(p)
+	r.read += int64(n)
+	if r.size <= 0 {
+		return n, e
+	}
+	if r.read > r.size || (e == io.EOF && r.read < r.size) {
+		return n, ErrFileSize
+	}
+	return n, e
+}
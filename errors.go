@@ -0,0 +1,125 @@
+package telebbb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Common telegram api errors, check them with errors.Is on error returned by any bot method
+var (
+	ErrUnauthorized       = errors.New("unauthorized, bot token is invalid")                  // 401 Unauthorized
+	ErrBotBlocked         = errors.New("bot was blocked by the user")                         // 403 Forbidden: bot was blocked by the user
+	ErrUserDeactivated    = errors.New("user is deactivated")                                 // 403 Forbidden: user is deactivated
+	ErrBotKicked          = errors.New("bot was kicked from the chat")                        // 403 Forbidden: bot was kicked from the group chat
+	ErrChatNotFound       = errors.New("chat not found")                                      // 400 Bad Request: chat not found
+	ErrMessageNotModified = errors.New("message is not modified")                             // 400 Bad Request: message is not modified
+	ErrMessageNotFound    = errors.New("message not found")                                   // 400 Bad Request: message to edit/delete not found
+	ErrWrongFileID        = errors.New("wrong file identifier")                               // 400 Bad Request: wrong file identifier/HTTP URL specified
+	ErrQueryTooOld        = errors.New("callback query is too old or already answered")       // 400 Bad Request: query is too old and response timeout expired or query ID is invalid
+	ErrChatMigrated       = errors.New("group chat was upgraded to a supergroup chat")        // 400 Bad Request with migrate_to_chat_id parameter
+	ErrTooManyRequests    = errors.New("too many requests, flood control exceeded")           // 429 Too Many Requests with retry_after parameter
+	ErrWebhookActive      = errors.New("can't use getUpdates method while webhook is active") // 409 Conflict
+)
+
+// apiErrorMatchers Tells which APIError matches to common error
+var apiErrorMatchers = map[error]func(a *APIError) bool{
+	ErrUnauthorized: func(a *APIError) bool {
+		return a.Code == http.StatusUnauthorized
+	},
+	ErrBotBlocked: func(a *APIError) bool {
+		return a.Code == http.StatusForbidden && a.contains("bot was blocked by the user")
+	},
+	ErrUserDeactivated: func(a *APIError) bool {
+		return a.Code == http.StatusForbidden && a.contains("user is deactivated")
+	},
+	ErrBotKicked: func(a *APIError) bool {
+		return a.Code == http.StatusForbidden && a.contains("bot was kicked")
+	},
+	ErrChatNotFound: func(a *APIError) bool {
+		return a.Code == http.StatusBadRequest && a.contains("chat not found")
+	},
+	ErrMessageNotModified: func(a *APIError) bool {
+		return a.Code == http.StatusBadRequest && a.contains("message is not modified")
+	},
+	ErrMessageNotFound: func(a *APIError) bool {
+		return a.Code == http.StatusBadRequest && (a.contains("message to edit not found") || a.contains("message to delete not found") || a.contains("message not found"))
+	},
+	ErrWrongFileID: func(a *APIError) bool {
+		return a.Code == http.StatusBadRequest && (a.contains("file identifier") || a.contains("file_id"))
+	},
+	ErrQueryTooOld: func(a *APIError) bool {
+		return a.Code == http.StatusBadRequest && (a.contains("query is too old") || a.contains("query id is invalid"))
+	},
+	ErrChatMigrated: func(a *APIError) bool {
+		return a.Parameters != nil && a.Parameters.MigrateToChatID != 0
+	},
+	ErrTooManyRequests: func(a *APIError) bool {
+		return a.Code == http.StatusTooManyRequests
+	},
+	ErrWebhookActive: func(a *APIError) bool {
+		return a.Code == http.StatusConflict && a.contains("webhook is active")
+	},
+}
+
+// APIError Error returned by telegram api when request was unsuccessful
+// Use errors.As to get it from any bot method error, or errors.Is with one of common errors like ErrBotBlocked
+type APIError struct {
+	Code        int                 `json:"error_code,omitempty"`  // Error code, usually same as http status code
+	Description string              `json:"description,omitempty"` // Human-readable description of the error
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`  // Optional. Information why request was unsuccessful, like flood control retry_after or migrate_to_chat_id
+}
+
+// Error Returns error text
+func (a *APIError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", a.Code, a.Description)
+}
+
+// Is Reports if api error matches one of common errors, used by errors.Is
+func (a *APIError) Is(target error) bool {
+	m, ok := apiErrorMatchers[target]
+	return ok && m(a)
+}
+
+// RetryAfter Returns number of seconds to wait before request can be repeated, 0 if not set
+func (a *APIError) RetryAfter() int {
+	if a.Parameters == nil {
+		return 0
+	}
+	return a.Parameters.RetryAfter
+}
+
+// MigrateToChatID Returns new supergroup identifier if group was migrated, 0 if not set
+func (a *APIError) MigrateToChatID() int {
+	if a.Parameters == nil {
+		return 0
+	}
+	return a.Parameters.MigrateToChatID
+}
+
+func (a *APIError) contains(s string) bool {
+	return strings.Contains(strings.ToLower(a.Description), s)
+}
+
+// checkResponce Checks telegram responce envelope and returns APIError if request was unsuccessful
+func checkResponce(status int, d []byte) error {
+	var r struct {
+		IsOk bool `json:"ok"`
+		APIError
+	}
+	if e := json.Unmarshal(d, &r); e != nil {
+		if status != http.StatusOK {
+			return fmt.Errorf("we got invalid status code responce, code responce is %d", status)
+		}
+		return e
+	}
+	if r.IsOk {
+		return nil
+	}
+	if r.Code == 0 {
+		r.Code = status
+	}
+	return &r.APIError
+}
@@ -0,0 +1,178 @@
+package telebbb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileCache Stores file_id of uploaded files by key made of file content hash and param name
+// Implementations must be safe for concurrent use
+type FileCache interface {
+	Get(key string) (fileID string, ok bool) // Get - returns cached file_id
+	Set(key string, fileID string) error     // Set - saves file_id of uploaded file
+}
+
+// cachedMethods Methods which return Message with file_id of uploaded file
+var cachedMethods = map[string]bool{
+	"sendPhoto":     true,
+	"sendAudio":     true,
+	"sendDocument":  true,
+	"sendVideo":     true,
+	"sendAnimation": true,
+	"sendVoice":     true,
+	"sendVideoNote": true,
+}
+
+// MemoryFileCache FileCache which keeps file_id in memory until program exits
+type MemoryFileCache struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewMemoryFileCache Returns empty in-memory cache
+func NewMemoryFileCache() *MemoryFileCache {
+	return &MemoryFileCache{ids: make(map[string]string)}
+}
+
+// Get Returns cached file_id
+func (c *MemoryFileCache) Get(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.ids[key]
+	return id, ok
+}
+
+// Set Saves file_id
+func (c *MemoryFileCache) Set(key string, fileID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[key] = fileID
+	return nil
+}
+
+// DiskFileCache FileCache which keeps file_id in JSON file, so they are kept between restarts
+type DiskFileCache struct {
+	mu   sync.RWMutex
+	path string
+	ids  map[string]string
+}
+
+// NewDiskFileCache Returns cache stored in file at path, file is created on first Set if it doesn't exist
+func NewDiskFileCache(path string) (*DiskFileCache, error) {
+	c := &DiskFileCache{path: path, ids: make(map[string]string)}
+	d, e := ioutil.ReadFile(path)
+	if os.IsNotExist(e) {
+		return c, nil
+	}
+	if e != nil {
+		return nil, e
+	}
+	if e = json.Unmarshal(d, &c.ids); e != nil {
+		return nil, fmt.Errorf("can't read file cache %s: %w", path, e)
+	}
+	return c, nil
+}
+
+// Get Returns cached file_id
+func (c *DiskFileCache) Get(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.ids[key]
+	return id, ok
+}
+
+// Set Saves file_id and rewrites cache file, file is replaced at once so it's never left half written
+func (c *DiskFileCache) Set(key string, fileID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[key] = fileID
+	d, e := json.Marshal(c.ids)
+	if e != nil {
+		return e
+	}
+	tmp, e := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if e != nil {
+		return e
+	}
+	_, e = tmp.Write(d)
+	if ce := tmp.Close(); e == nil {
+		e = ce
+	}
+	if e == nil {
+		e = os.Rename(tmp.Name(), c.path)
+	}
+	if e != nil {
+		os.Remove(tmp.Name())
+	}
+	return e
+}
+
+// hash Returns sha256 of file content, reader is returned to it's position so file still can be uploaded
+func (f *InputFile) hash() (string, error) {
+	h := sha256.New()
+	if f.path != "" {
+		r, e := os.Open(f.path)
+		if e != nil {
+			return "", e
+		}
+		defer r.Close()
+		if _, e = io.Copy(h, r); e != nil {
+			return "", e
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	s, ok := f.reader.(io.ReadSeeker)
+	if !ok {
+		return "", fmt.Errorf("input file %s can't be read twice to get it's hash", f.name)
+	}
+	pos, e := s.Seek(0, io.SeekCurrent)
+	if e != nil {
+		return "", e
+	}
+	if _, e = io.Copy(h, s); e != nil {
+		return "", e
+	}
+	if _, e = s.Seek(pos, io.SeekStart); e != nil {
+		return "", e
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sendCached Sends file by cached file_id if same content was already uploaded, otherwise uploads it and caches file_id
+// If telegram rejects cached file_id file is uploaded again and new file_id replaces stale one, other errors are returned as is
+func (t *TbBot) sendCached(ctx context.Context, method string, name string, message interface{}, file *InputFile) ([]byte, error) {
+	hash, e := file.hash()
+	if e != nil { // file can't be cached, but it still can be sent
+		return t.sendFiles(ctx, method, name, message, file)
+	}
+	key := hash + ":" + name
+	if id, ok := t.fileCache.Get(key); ok {
+		resp, e := t.sendFiles(ctx, method, name, message, FileID(id))
+		if !errors.Is(e, ErrWrongFileID) {
+			file.close()
+			return resp, e
+		}
+	}
+	resp, e := t.sendFiles(ctx, method, name, message, file)
+	if e != nil {
+		return nil, e
+	}
+	var r responce
+	if json.Unmarshal(resp, &r) == nil {
+		if id := r.Type.MediaFileID(); id != "" {
+			if e := t.fileCache.Set(key, id); e != nil {
+				t.reportError(e)
+			}
+		}
+	}
+	return resp, nil
+}
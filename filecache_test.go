@@ -0,0 +1,66 @@
+package telebbb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSendCachedFallback Checks that file is uploaded again only when telegram rejects cached file_id
+func TestSendCachedFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string // error returned for send by cached file_id
+		uploads     int    // uploads expected after first one
+		want        error
+		cached      string // file_id expected in cache after send
+	}{
+		{"wrong file_id", "Bad Request: wrong file identifier/HTTP URL specified", 1, nil, "F2"},
+		{"chat not found", "Bad Request: chat not found", 0, ErrChatNotFound, "F1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"ok":false,"error_code":400,"description":"` + tt.description + `"}`))
+					return
+				}
+				uploads++
+				id := "F1"
+				if uploads > 1 {
+					id = "F2"
+				}
+				w.Write([]byte(`{"ok":true,"result":{"message_id":1,"document":{"file_id":"` + id + `","file_unique_id":"u"}}}`))
+			}))
+			defer srv.Close()
+
+			cache := NewMemoryFileCache()
+			b, e := NewBot(BotConfig{Type: "none", Token: "test", APIURL: srv.URL, FileCache: cache, Limits: RateLimits{Disabled: true}})
+			if e != nil {
+				t.Fatal(e)
+			}
+			send := func() error {
+				_, e := b.SendDocument(SendDocumentType{ChatID: 1}, FileReader("a.txt", strings.NewReader("cached content")))
+				return e
+			}
+			if e = send(); e != nil {
+				t.Fatal(e)
+			}
+			if e = send(); !errors.Is(e, tt.want) {
+				t.Errorf("second send returned %v, want %v", e, tt.want)
+			}
+			if uploads-1 != tt.uploads {
+				t.Errorf("file was uploaded again %d times, want %d", uploads-1, tt.uploads)
+			}
+			for k, id := range cache.ids {
+				if id != tt.cached {
+					t.Errorf("cache has %s = %s, want %s", k, id, tt.cached)
+				}
+			}
+		})
+	}
+}
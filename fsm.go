@@ -0,0 +1,270 @@
+package telebbb
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultCancelCommand = "cancel" // command which stops conversation when Conversation.CancelCommand is not set
+
+// Session Conversation state of one user in one chat
+type Session struct {
+	State   string            // State - current state name
+	Data    map[string]string // Data - values collected during conversation, for example user answers
+	Updated time.Time         // Updated - last time session was saved, used for timeouts
+}
+
+// StateStorage Keeps conversation sessions by key, implementations must be safe for concurrent use
+type StateStorage interface {
+	Get(key string) (*Session, error) // Get - returns session or nil if there is no conversation
+	Set(key string, s *Session) error // Set - saves session
+	Delete(key string) error          // Delete - removes session when conversation is finished
+}
+
+// MemoryStateStorage StateStorage which keeps sessions in memory until program exits
+type MemoryStateStorage struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewMemoryStateStorage Returns empty in-memory storage
+func NewMemoryStateStorage() *MemoryStateStorage {
+	return &MemoryStateStorage{sessions: make(map[string]*Session)}
+}
+
+// Get Returns copy of session or nil if there is no conversation
+func (m *MemoryStateStorage) Get(key string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[key]
+	if !ok {
+		return nil, nil
+	}
+	return s.copy(), nil
+}
+
+// Set Saves copy of session
+func (m *MemoryStateStorage) Set(key string, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions == nil {
+		m.sessions = make(map[string]*Session)
+	}
+	m.sessions[key] = s.copy()
+	return nil
+}
+
+// Delete Removes session
+func (m *MemoryStateStorage) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, key)
+	return nil
+}
+
+// copy Returns session copy, so handlers can't change stored session without saving it
+func (s *Session) copy() *Session {
+	cp := *s
+	cp.Data = make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		cp.Data[k] = v
+	}
+	return &cp
+}
+
+// Conversation Multi-step dialog, while user has active session their updates are passed to handler of current state
+// instead of dispatcher routes. Conversation is started by any handler with Context.SetState. Add it to dispatcher with Use(c.Middleware())
+// Zero value keeps sessions in memory and is ready to use
+type Conversation struct {
+	Storage       StateStorage           // Storage - where sessions are kept, default is in-memory storage
+	Timeout       time.Duration          // Timeout - session without updates for this time is finished, zero means no timeout
+	CancelCommand string                 // CancelCommand - command which finishes conversation, default is "cancel"
+	OnCancel      HandlerFunc            // OnCancel - called when user cancels conversation, Context has canceled session state
+	OnTimeout     HandlerFunc            // OnTimeout - called on first update after session timed out, Context has expired session state. If it calls SetState update is not routed further
+	Key           func(u *Update) string // Key - updates with same key share session, default is chat and user id
+
+	mu     sync.RWMutex
+	states map[string]HandlerFunc
+}
+
+// NewConversation Creates conversation with sessions kept in storage, nil storage keeps sessions in memory
+func NewConversation(storage StateStorage) *Conversation {
+	if storage == nil {
+		storage = NewMemoryStateStorage()
+	}
+	return &Conversation{Storage: storage, states: make(map[string]HandlerFunc)}
+}
+
+// State Registers handler for updates of users in state
+func (cv *Conversation) State(state string, h HandlerFunc) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	if cv.states == nil {
+		cv.states = make(map[string]HandlerFunc)
+	}
+	cv.states[state] = h
+}
+
+// Middleware Returns dispatcher middleware which loads session of update, routes update to state handler
+// and saves session after handler
+func (cv *Conversation) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			key := cv.key(c.Update)
+			if key == "" { // update without chat and user, like poll, can't be part of conversation
+				return next(c)
+			}
+			s, e := cv.storage().Get(key)
+			if e != nil {
+				return e
+			}
+			if s != nil && s.Data == nil {
+				s.Data = make(map[string]string)
+			}
+			c.session = &sessionRef{key: key, session: s}
+
+			// Timed out session is finished and update is handled as if there was no conversation
+			if s != nil && cv.Timeout > 0 && time.Since(s.Updated) > cv.Timeout {
+				c.session.finished = true
+				if cv.OnTimeout != nil {
+					e = cv.OnTimeout(c)
+					if e != nil || c.session.restarted { // OnTimeout started new conversation, so update is already handled
+						if se := cv.save(c.session); se != nil && e == nil {
+							e = se
+						}
+						return e
+					}
+				}
+				c.session.session, s = nil, nil
+			}
+
+			var h HandlerFunc
+			switch {
+			case s == nil:
+				h = next
+			case c.Command != "" && c.Command == cv.cancelCommand():
+				c.session.finished = true
+				h = cv.OnCancel
+			default:
+				cv.mu.RLock()
+				h = cv.states[s.State]
+				cv.mu.RUnlock()
+				if h == nil {
+					return fmt.Errorf("conversation has no handler for state %q", s.State)
+				}
+			}
+			if h != nil {
+				e = h(c)
+			}
+			if se := cv.save(c.session); se != nil && e == nil {
+				e = se
+			}
+			return e
+		}
+	}
+}
+
+// save Saves session changed by handler or deletes finished session
+func (cv *Conversation) save(r *sessionRef) error {
+	switch {
+	case r.finished && r.session != nil && r.restarted:
+		r.session.Updated = time.Now()
+		return cv.storage().Set(r.key, r.session)
+	case r.finished:
+		return cv.storage().Delete(r.key)
+	case r.session != nil:
+		r.session.Updated = time.Now()
+		return cv.storage().Set(r.key, r.session)
+	}
+	return nil
+}
+
+// storage Returns session storage, in-memory storage is created if Storage is not set
+func (cv *Conversation) storage() StateStorage {
+	cv.mu.RLock()
+	st := cv.Storage
+	cv.mu.RUnlock()
+	if st != nil {
+		return st
+	}
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	if cv.Storage == nil {
+		cv.Storage = NewMemoryStateStorage()
+	}
+	return cv.Storage
+}
+
+// key Returns session key of update
+func (cv *Conversation) key(u *Update) string {
+	if cv.Key != nil {
+		return cv.Key(u)
+	}
+	chat, user := u.Chat(), u.Sender()
+	switch {
+	case chat != nil && user != nil:
+		return strconv.Itoa(chat.ID) + ":" + strconv.Itoa(user.ID)
+	case chat != nil:
+		return strconv.Itoa(chat.ID) + ":"
+	case user != nil:
+		return ":" + strconv.Itoa(user.ID)
+	}
+	return ""
+}
+
+// cancelCommand Returns command name which cancels conversation
+func (cv *Conversation) cancelCommand() string {
+	if cv.CancelCommand == "" {
+		return defaultCancelCommand
+	}
+	return cv.CancelCommand
+}
+
+// sessionRef Session of update being handled
+type sessionRef struct {
+	key       string
+	session   *Session
+	finished  bool // session has to be deleted after handler
+	restarted bool // handler started new conversation after old one was finished
+}
+
+// State Returns conversation state of update sender, empty string if there is no conversation
+func (c *Context) State() string {
+	if c.session == nil || c.session.session == nil {
+		return ""
+	}
+	return c.session.session.State
+}
+
+// Data Returns values saved in conversation, changes are saved after handler returns. Nil if there is no conversation
+func (c *Context) Data() map[string]string {
+	if c.session == nil || c.session.session == nil {
+		return nil
+	}
+	return c.session.session.Data
+}
+
+// SetState Moves conversation to state, conversation is started if there is no one. State is saved after handler returns
+func (c *Context) SetState(state string) error {
+	if c.session == nil {
+		return fmt.Errorf("conversation middleware is not used or update has no chat and user")
+	}
+	if c.session.session == nil || (c.session.finished && !c.session.restarted) {
+		c.session.session = &Session{Data: make(map[string]string)}
+		c.session.restarted = c.session.finished
+	}
+	c.session.session.State = state
+	return nil
+}
+
+// Finish Ends conversation, session is deleted after handler returns
+func (c *Context) Finish() error {
+	if c.session == nil {
+		return fmt.Errorf("conversation middleware is not used or update has no chat and user")
+	}
+	c.session.finished = true
+	c.session.restarted = false
+	return nil
+}
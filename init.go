@@ -3,29 +3,81 @@ package telebbb
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 )
 
+const (
+	defaultPollTimeout = 30               // long polling timeout in seconds used when BotConfig.PollTimeout is not set
+	defaultTimeout     = time.Second * 10 // api request timeout used when BotConfig.Timeout is not set
+	incomingBuffer     = 100              // how many updates can wait in Incoming channel before listeners are blocked
+)
+
 // NewBot Starts new bot service
 // To run new bot service please provide
 // telebbb.BotConfig type with data
+// Listeners started by NewBot work until program exits, use "none" type and call
+// LocalListenContext or ServeHookContext yourself if you need to stop them
 func NewBot(c BotConfig) (*TbBot, error) {
-	cli := &http.Client{
-		Timeout: time.Second * 10,
+	// Requests are limited by context, so default client has no own timeout
+	cli := &http.Client{}
+	if c.Client != nil {
+		cli = c.Client
+	}
+	if c.Transport != nil {
+		cp := *cli // don't change client owned by caller
+		cp.Transport = c.Transport
+		cli = &cp
+	}
+	if c.PollTimeout <= 0 {
+		c.PollTimeout = defaultPollTimeout
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	c.APIURL = strings.TrimSuffix(c.APIURL, "/")
+	if c.APIURL == "" {
+		c.APIURL = DefaultAPIURL
+	}
+	c.FileURL = strings.TrimSuffix(c.FileURL, "/")
+	if c.FileURL == "" {
+		c.FileURL = c.APIURL + "/file"
 	}
 
 	// Create Connection and start webhook or
 	b := &TbBot{
-		client:   cli,
-		token:    c.Token,
-		Incoming: make(chan interface{}),
-		Errors:   make(chan error, 1),
+		client:         cli,
+		token:          c.Token,
+		timeout:        c.Timeout,
+		apiURL:         c.APIURL,
+		fileURL:        c.FileURL,
+		pollTimeout:    c.PollTimeout,
+		allowedUpdates: c.AllowedUpdates,
+		hookPath:       c.WebhookPath,
+		hookSecret:     c.WebhookSecret,
+		retry:          c.Retry,
+		limit:          newLimiter(c.Limits),
+		middleware:     c.Middleware,
+		uploads:        c.Uploads,
+		fileCache:      c.FileCache,
+		Incoming:       make(chan *Update, incomingBuffer),
+		Errors:         make(chan error, 1),
 	}
 
 	// Start Bot update listner
 	switch c.Type {
 	case "webhook":
-		go b.ServeHook(c.Port) // webhook listner
+		if c.WebhookURL != "" {
+			if e := b.registerHook(c); e != nil {
+				return nil, e
+			}
+		}
+		go func() { // webhook listner
+			if e := b.ServeHook(c.Port); e != nil {
+				b.reportError(e)
+			}
+		}()
 	case "local":
 		go b.LocalListen() // local listner
 	case "none": // Don't listen for telegram msgs ignore
@@ -35,3 +87,31 @@ func NewBot(c BotConfig) (*TbBot, error) {
 	}
 	return b, nil
 }
+
+// registerHook Registers webhook url from config with setWebhook, uploads certificate if it's path provided
+func (b *TbBot) registerHook(c BotConfig) error {
+	var cert *os.File
+	if c.WebhookCert != "" {
+		f, e := os.Open(c.WebhookCert)
+		if e != nil {
+			return e
+		}
+		cert = f
+	}
+	if _, e := b.SetWebhook(SetWebhookType{
+		URL:            c.WebhookURL,
+		AllowedUpdates: c.AllowedUpdates,
+		SecretToken:    c.WebhookSecret,
+	}, cert); e != nil {
+		return fmt.Errorf("can't register webhook: %w", e)
+	}
+	return nil
+}
+
+// reportError Sends error to Errors channel without blocking, if nobody reads Errors the error is dropped
+func (b *TbBot) reportError(e error) {
+	select {
+	case b.Errors <- e:
+	default:
+	}
+}
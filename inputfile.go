@@ -0,0 +1,146 @@
+package telebbb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// InputFile File to send, can be file_id or URL of file telegram already can get, local file path or any reader
+// Use FileID, FileURL, FilePath or FileReader to create it. InputFile can be passed to upload methods or set as
+// file field of message struct, for example SendPhotoType.Photo
+type InputFile struct {
+	id     string    // file_id or URL, file is not uploaded
+	path   string    // local file path
+	name   string    // file name sent to telegram
+	reader io.Reader // file content if file was created from reader
+	file   *os.File  // reader if it's *os.File, kept to close it after reader is wrapped
+	read   bool      // reader was already read, it has to be rewinded before next upload
+}
+
+// FileID Returns InputFile of file which exists on telegram servers
+func FileID(id string) *InputFile {
+	return &InputFile{id: id}
+}
+
+// FileURL Returns InputFile of file which telegram will get from the Internet by HTTP URL
+func FileURL(url string) *InputFile {
+	return &InputFile{id: url}
+}
+
+// FilePath Returns InputFile of local file which will be uploaded, file is opened only during upload
+func FilePath(path string) *InputFile {
+	return &InputFile{path: path, name: filepath.Base(path)}
+}
+
+// FileReader Returns InputFile which content is read from r and uploaded with name as file name
+// If r is io.Seeker upload can be retried, *os.File readers are closed after upload
+func FileReader(name string, r io.Reader) *InputFile {
+	f := &InputFile{name: filepath.Base(name), reader: r}
+	f.file, _ = r.(*os.File)
+	return f
+}
+
+// upload Reports if file content has to be uploaded
+func (f *InputFile) upload() bool {
+	return f.id == ""
+}
+
+// open Returns file content, reader is rewinded if it was already read
+func (f *InputFile) open() (io.ReadCloser, error) {
+	if f.path != "" {
+		return os.Open(f.path)
+	}
+	if f.reader == nil {
+		return nil, fmt.Errorf("input file %s has no content", f.name)
+	}
+	if f.read {
+		s, ok := f.reader.(io.Seeker)
+		if !ok {
+			return nil, fmt.Errorf("input file %s can't be read again", f.name)
+		}
+		if _, e := s.Seek(0, io.SeekStart); e != nil {
+			return nil, e
+		}
+	}
+	f.read = true
+	return ioutil.NopCloser(f.reader), nil
+}
+
+// close Closes *os.File reader after upload
+func (f *InputFile) close() {
+	if f.file != nil {
+		f.file.Close()
+	}
+}
+
+// MarshalJSON Sends file_id or URL as string, uploaded files are sent as separate form parts
+func (f *InputFile) MarshalJSON() ([]byte, error) {
+	if !f.upload() {
+		return json.Marshal(f.id)
+	}
+	return []byte("null"), nil
+}
+
+// String Returns file_id, URL or name of uploaded file
+func (f *InputFile) String() string {
+	if !f.upload() {
+		return f.id
+	}
+	return f.name
+}
+
+// attachMedia Names uploaded files in Media and Thumb fields of InputMedia items and returns them as form files
+// with JSON-serialized media, where uploaded files are referenced as attach://<name>. Media items are not changed
+func attachMedia(media interface{}) (d []byte, files []formFile, e error) {
+	v := reflect.ValueOf(media)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, nil, nil
+	}
+	items := make([]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items[i] = v.Index(i).Interface()
+		item := v.Index(i)
+		for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+			if item.IsNil() {
+				break
+			}
+			item = item.Elem()
+		}
+		if item.Kind() != reflect.Struct {
+			continue
+		}
+		for _, field := range []string{"Media", "Thumb"} {
+			sf, ok := item.Type().FieldByName(field)
+			if !ok || sf.PkgPath != "" {
+				continue
+			}
+			if f, ok := item.FieldByIndex(sf.Index).Interface().(*InputFile); ok && f != nil && f.upload() {
+				// Item copy is sent with attach:// reference instead of file, so caller's InputFile stays untouched
+				name := fmt.Sprintf("%s%d", strings.ToLower(field), i)
+				p, e := withParam(items[i], jsonName(sf), "attach://"+name)
+				if e != nil {
+					return nil, nil, e
+				}
+				items[i] = p
+				files = append(files, formFile{name: name, file: f})
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil, nil
+	}
+	d, e = json.Marshal(items)
+	return d, files, e
+}
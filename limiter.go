@@ -0,0 +1,183 @@
+package telebbb
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGlobalLimit  = 30   // messages per second for all chats
+	defaultPrivateLimit = 1    // messages per second to one private chat
+	defaultGroupLimit   = 20   // messages per minute to one group or channel
+	limiterCleanupSize  = 1024 // chats map is cleaned from old entries when it grows bigger than this
+)
+
+// limitedMethods Telegram methods which send messages to chat and are paced by limiter
+var limitedMethods = map[string]bool{
+	"sendMessage":    true,
+	"forwardMessage": true,
+	"copyMessage":    true,
+	"sendPhoto":      true,
+	"sendAudio":      true,
+	"sendDocument":   true,
+	"sendVideo":      true,
+	"sendAnimation":  true,
+	"sendVoice":      true,
+	"sendVideoNote":  true,
+	"sendMediaGroup": true,
+	"sendLocation":   true,
+	"sendVenue":      true,
+	"sendContact":    true,
+	"sendPoll":       true,
+	"sendDice":       true,
+	"sendSticker":    true,
+	"sendInvoice":    true,
+	"sendGame":       true,
+}
+
+// RateLimits Pacing of outgoing messages, zero values use telegram limits
+// Only methods which send messages to chat are paced, methods like getMe or getUpdates are never limited
+type RateLimits struct {
+	Disabled bool     // Disabled - turns limiter off
+	Global   int      // Global - messages per second for all chats together, default is 30
+	Private  int      // Private - messages per second to one private chat, default is 1
+	Group    int      // Group - messages per minute to one group, supergroup or channel, default is 20
+	Bypass   []string // Bypass - method names which are sent without waiting for limiter, for example "sendChatAction"
+}
+
+// limiter Waits before sending message so telegram per chat and global limits are not exceeded
+type limiter struct {
+	mu      sync.Mutex
+	global  time.Duration        // interval between any two messages
+	private time.Duration        // interval between two messages to one private chat
+	group   time.Duration        // interval between two messages to one group or channel
+	next    time.Time            // time when next message can be sent to any chat
+	chats   map[string]time.Time // time when next message can be sent to chat
+	bypass  map[string]bool
+}
+
+// newLimiter Creates limiter from config, returns nil if limits are disabled
+func newLimiter(c RateLimits) *limiter {
+	if c.Disabled {
+		return nil
+	}
+	if c.Global <= 0 {
+		c.Global = defaultGlobalLimit
+	}
+	if c.Private <= 0 {
+		c.Private = defaultPrivateLimit
+	}
+	if c.Group <= 0 {
+		c.Group = defaultGroupLimit
+	}
+	l := &limiter{
+		global:  time.Second / time.Duration(c.Global),
+		private: time.Second / time.Duration(c.Private),
+		group:   time.Minute / time.Duration(c.Group),
+		chats:   make(map[string]time.Time),
+		bypass:  make(map[string]bool),
+	}
+	for _, m := range c.Bypass {
+		l.bypass[m] = true
+	}
+	return l
+}
+
+// wait Blocks until message can be sent to chat with method or ctx is done
+// chatID is raw JSON value of chat_id param, messages without chat_id are paced only by global limit
+func (l *limiter) wait(ctx context.Context, method string, chatID json.RawMessage) error {
+	if l == nil || !limitedMethods[method] || l.bypass[method] {
+		return nil
+	}
+	d, release := l.reserve(chatKey(chatID), time.Now())
+	if d <= 0 {
+		return nil
+	}
+	if e := sleep(ctx, d); e != nil {
+		release()
+		return e
+	}
+	return nil
+}
+
+// reserve Books time slot for next message to chat and returns how long to wait for it
+// release gives slot back if message won't be sent, slots booked after it stay as they are
+func (l *limiter) reserve(chat string, now time.Time) (wait time.Duration, release func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slot := now
+	if l.next.After(slot) {
+		slot = l.next
+	}
+	prevNext, prevChat, hadChat := l.next, time.Time{}, false
+	if chat != "" {
+		prevChat, hadChat = l.chats[chat]
+		if prevChat.After(slot) {
+			slot = prevChat
+		}
+		l.chats[chat] = slot.Add(l.chatInterval(chat))
+	}
+	next, chatNext := slot.Add(l.global), l.chats[chat]
+	l.next = next
+	release = func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.next.Equal(next) {
+			l.next = prevNext
+		}
+		if chat == "" {
+			return
+		}
+		if cur, ok := l.chats[chat]; ok && cur.Equal(chatNext) {
+			if hadChat {
+				l.chats[chat] = prevChat
+			} else {
+				delete(l.chats, chat)
+			}
+		}
+	}
+	if len(l.chats) > limiterCleanupSize {
+		for k, v := range l.chats {
+			if v.Before(now) {
+				delete(l.chats, k)
+			}
+		}
+	}
+	return slot.Sub(now), release
+}
+
+// chatInterval Positive chat identifiers belong to private chats, negative ones and @username to groups and channels
+func (l *limiter) chatInterval(chat string) time.Duration {
+	if strings.HasPrefix(chat, "-") || strings.HasPrefix(chat, "@") {
+		return l.group
+	}
+	return l.private
+}
+
+// chatKey Returns limiter key of chat_id value, so same chat sent as number and as string gets same key
+func chatKey(chatID json.RawMessage) string {
+	key := string(chatID)
+	var s string
+	if json.Unmarshal(chatID, &s) == nil {
+		key = strings.TrimSpace(s)
+	}
+	if id, e := strconv.ParseInt(key, 10, 64); e == nil {
+		return strconv.FormatInt(id, 10)
+	}
+	return key
+}
+
+// chatIDParam Returns raw chat_id value from JSON encoded params
+func chatIDParam(params []byte) json.RawMessage {
+	var p struct {
+		ChatID json.RawMessage `json:"chat_id"`
+	}
+	if e := json.Unmarshal(params, &p); e != nil {
+		return nil
+	}
+	return p.ChatID
+}
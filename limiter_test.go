@@ -0,0 +1,37 @@
+package telebbb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestLimiterChatKey Checks that chat is paced by same key and interval whether chat_id is sent as number or string
+func TestLimiterChatKey(t *testing.T) {
+	l := newLimiter(RateLimits{})
+	tests := []struct {
+		params   interface{}
+		key      string
+		interval time.Duration
+	}{
+		{SendMessageType{ChatID: 5}, "5", l.private},
+		{SendMessageType{ChatID: "5"}, "5", l.private},
+		{SendMessageType{ChatID: int64(-1001234567890)}, "-1001234567890", l.group},
+		{SendMessageType{ChatID: "-1001234567890"}, "-1001234567890", l.group},
+		{SendMessageType{ChatID: "@channel"}, "@channel", l.group},
+		{SendMessageType{Text: "no chat"}, "", l.private},
+	}
+	for _, tt := range tests {
+		d, e := json.Marshal(tt.params)
+		if e != nil {
+			t.Fatal(e)
+		}
+		key := chatKey(chatIDParam(d))
+		if key != tt.key {
+			t.Errorf("chat key of %s = %q, want %q", d, key, tt.key)
+		}
+		if i := l.chatInterval(key); key != "" && i != tt.interval {
+			t.Errorf("chat interval of %s = %s, want %s", d, i, tt.interval)
+		}
+	}
+}
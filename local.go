@@ -1,35 +1,59 @@
 package telebbb
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
+	"context"
 	"time"
 )
 
-// LocalListen Listen for bot updates local with for loop every second
+const (
+	minPollBackoff = time.Second // first delay after failed getUpdates call
+	maxPollBackoff = time.Minute // delay is doubled on every failed call but not more than this
+)
+
+// LocalListen Listen for bot updates with getUpdates long polling
+// Every recieved update is confirmed by offset on the next call, so it will be delivered only once
+// On network errors listener waits with exponential backoff and reports error to Errors channel
 func (b *TbBot) LocalListen() {
+	b.LocalListenContext(context.Background())
+}
+
+// LocalListenContext Same as LocalListen, but stops listening and returns ctx error when ctx is done
+func (b *TbBot) LocalListenContext(ctx context.Context) error {
+	offset := 0
+	backoff := minPollBackoff
+	// Every poll can take up to long polling timeout plus usual request time
+	pollTimeout := time.Duration(b.pollTimeout)*time.Second + b.timeout
 	for {
-		time.Sleep(time.Minute * 5)
-		r, err := http.Get(fmt.Sprintf(URL, b.token, "getUpdates"))
-		if err != nil {
-			b.Errors <- err
-			time.Sleep(time.Minute)
-		}
-		if r.StatusCode == http.StatusBadRequest {
-			continue
+		pctx, cancel := context.WithTimeout(ctx, pollTimeout)
+		u, err := b.GetUpdatesContext(pctx, GetUpdatesType{
+			Offset:         offset,
+			Timeout:        b.pollTimeout,
+			AllowedUpdates: b.allowedUpdates,
+		})
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
-		d, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			b.Errors <- err
-			time.Sleep(time.Minute)
+			b.reportError(err)
+			if err = sleep(ctx, backoff); err != nil {
+				return err
+			}
+			if backoff *= 2; backoff > maxPollBackoff {
+				backoff = maxPollBackoff
+			}
+			continue
 		}
-		var i interface{}
-		if err = json.Unmarshal(d, &i); err != nil {
-			b.Errors <- err
-			time.Sleep(time.Minute)
+		backoff = minPollBackoff
+		for i := range u {
+			if u[i].UpdateID >= offset {
+				offset = u[i].UpdateID + 1
+			}
+			select {
+			case b.Incoming <- &u[i]:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		}
-		b.Incoming <- i
 	}
 }
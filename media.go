@@ -0,0 +1,36 @@
+package telebbb
+
+// LargestPhoto Returns biggest available size of message photo, nil if message has no photo
+func (m *Message) LargestPhoto() *PhotoSize {
+	var p *PhotoSize
+	for _, s := range m.Photo {
+		if s != nil && (p == nil || s.Width*s.Height > p.Width*p.Height) {
+			p = s
+		}
+	}
+	return p
+}
+
+// MediaFileID Returns file_id of message media, for photo file_id of largest size is returned
+// Empty string is returned if message has no media
+func (m *Message) MediaFileID() string {
+	switch {
+	case m.LargestPhoto() != nil:
+		return m.LargestPhoto().FileID
+	case m.Animation != nil: // animation messages also have document set, so it's checked first
+		return m.Animation.FileID
+	case m.Document != nil:
+		return m.Document.FileID
+	case m.Audio != nil:
+		return m.Audio.FileID
+	case m.Video != nil:
+		return m.Video.FileID
+	case m.VideoNote != nil:
+		return m.VideoNote.FileID
+	case m.Voice != nil:
+		return m.Voice.FileID
+	case m.Sticker != nil:
+		return m.Sticker.FileID
+	}
+	return ""
+}
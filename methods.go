@@ -2,14 +2,17 @@ package telebbb
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 /*
@@ -21,8 +24,12 @@ import (
 */
 
 // URL contains main telegram url to place our calls
+// Requests are made to BotConfig.APIURL, this format is kept for default api server
 const URL = "https://api.telegram.org/bot%s/%s"
 
+// DefaultAPIURL Base url of telegram bot api server
+const DefaultAPIURL = "https://api.telegram.org"
+
 type responce struct {
 	IsOk bool    `json:"ok,omitempty"`
 	Type Message `json:"result,omitempty"`
@@ -30,11 +37,16 @@ type responce struct {
 
 // GetMe returns User infor about our bot
 func (t *TbBot) GetMe() (u *User, e error) {
+	return t.GetMeContext(context.Background())
+}
+
+// GetMeContext Same as GetMe, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetMeContext(ctx context.Context) (u *User, e error) {
 	type responce struct {
 		IsOk bool `json:"ok,omitempty"`
 		Type User `json:"result,omitempty"`
 	}
-	resp, e := t.sendGet("getMe")
+	resp, e := t.sendGet(ctx, "getMe")
 	if e != nil {
 		return
 	}
@@ -51,13 +63,175 @@ func (t *TbBot) GetMe() (u *User, e error) {
 	return
 }
 
+// LogOut Use this method to log out from the cloud Bot API server before launching the bot locally. You must log out the bot before running it locally, otherwise there is no guarantee that the bot will receive updates. After a successful call, you can immediately log in on a local server, but will not be able to log in back to the cloud Bot API server for 10 minutes. Returns True on success.
+func (t *TbBot) LogOut() (m bool, e error) {
+	return t.LogOutContext(context.Background())
+}
+
+// LogOutContext Same as LogOut, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) LogOutContext(ctx context.Context) (m bool, e error) {
+	resp, e := t.sendGet(ctx, "logOut")
+	if e != nil {
+		return
+	}
+	// Working with responce
+	var r responce
+	if e = json.Unmarshal(resp, &r); e != nil {
+		return
+	}
+	m = r.IsOk
+	return
+}
+
+// Close Use this method to close the bot instance before moving it from one local server to another. You need to delete the webhook before calling this method to ensure that the bot isn't launched again after server restart. The method will return error 429 in the first 10 minutes after the bot is launched. Returns True on success.
+func (t *TbBot) Close() (m bool, e error) {
+	return t.CloseContext(context.Background())
+}
+
+// CloseContext Same as Close, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) CloseContext(ctx context.Context) (m bool, e error) {
+	resp, e := t.sendGet(ctx, "close")
+	if e != nil {
+		return
+	}
+	// Working with responce
+	var r responce
+	if e = json.Unmarshal(resp, &r); e != nil {
+		return
+	}
+	m = r.IsOk
+	return
+}
+
+// GetUpdates Use this method to receive incoming updates using long polling. Returns an Array of Update objects. Accepts GetUpdatesType struct, but can accept interface if needed.
+// Request is not limited by BotConfig.Timeout so long polling timeout param can be bigger, use ctx deadline to limit it
+func (t *TbBot) GetUpdates(message interface{}) (m []Update, e error) {
+	return t.GetUpdatesContext(context.Background(), message)
+}
+
+// GetUpdatesContext Same as GetUpdates, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetUpdatesContext(ctx context.Context, message interface{}) (m []Update, e error) {
+	if message == nil {
+		e = fmt.Errorf("message can't be nil")
+		return
+	}
+	resp, e := t.post(ctx, message, "getUpdates", 0)
+	if e != nil {
+		return
+	}
+	var r struct {
+		IsOk bool     `json:"ok,omitempty"`
+		Type []Update `json:"result,omitempty"`
+	}
+	if e = json.Unmarshal(resp, &r); e != nil {
+		return
+	}
+	if r.IsOk {
+		m = r.Type
+	} else {
+		e = fmt.Errorf("we got 200 responce but have false in status returned struct %+v", r)
+	}
+	return
+}
+
+// SetWebhook Use this method to specify a url and receive incoming updates via an outgoing webhook. Returns True on success. Accepts SetWebhookType struct, but can accept interface if needed.
+// Send certificate file if you use self-signed certificate, otherwise send nil
+func (t *TbBot) SetWebhook(message interface{}, file *os.File) (m bool, e error) {
+	return t.SetWebhookContext(context.Background(), message, file)
+}
+
+// SetWebhookContext Same as SetWebhook, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SetWebhookContext(ctx context.Context, message interface{}, file *os.File) (m bool, e error) {
+	if message == nil {
+		e = fmt.Errorf("message can't be nil")
+		return
+	}
+	var resp []byte
+	if file != nil {
+		resp, e = t.uploadFile(ctx, file, "setWebhook", "certificate", message)
+		if e != nil {
+			return false, e
+		}
+	} else {
+		resp, e = t.sendPost(ctx, message, "setWebhook")
+		if e != nil {
+			return
+		}
+	}
+	var r responce
+	if e = json.Unmarshal(resp, &r); e != nil {
+		return
+	}
+	if r.IsOk {
+		m = r.IsOk
+	} else {
+		e = fmt.Errorf("we got 200 responce but have false in status returned struct %+v", r)
+	}
+	return
+}
+
+// DeleteWebhook Use this method to remove webhook integration if you decide to switch back to getUpdates. Returns True on success. Accepts DeleteWebhookType struct, but can accept interface if needed.
+func (t *TbBot) DeleteWebhook(message interface{}) (m bool, e error) {
+	return t.DeleteWebhookContext(context.Background(), message)
+}
+
+// DeleteWebhookContext Same as DeleteWebhook, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) DeleteWebhookContext(ctx context.Context, message interface{}) (m bool, e error) {
+	if message == nil {
+		e = fmt.Errorf("message can't be nil")
+		return
+	}
+	resp, e := t.sendPost(ctx, message, "deleteWebhook")
+	if e != nil {
+		return
+	}
+	// Working with responce
+	var r responce
+	if e = json.Unmarshal(resp, &r); e != nil {
+		return
+	}
+	m = r.IsOk
+	return
+}
+
+// GetWebhookInfo Use this method to get current webhook status. On success, returns a WebhookInfo object. If the bot is using getUpdates, will return an object with the url field empty.
+func (t *TbBot) GetWebhookInfo() (m *WebhookInfo, e error) {
+	return t.GetWebhookInfoContext(context.Background())
+}
+
+// GetWebhookInfoContext Same as GetWebhookInfo, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetWebhookInfoContext(ctx context.Context) (m *WebhookInfo, e error) {
+	resp, e := t.sendGet(ctx, "getWebhookInfo")
+	if e != nil {
+		return
+	}
+	var r struct {
+		IsOk bool        `json:"ok,omitempty"`
+		Type WebhookInfo `json:"result,omitempty"`
+	}
+	if e = json.Unmarshal(resp, &r); e != nil {
+		return
+	}
+	if r.IsOk {
+		m = &r.Type
+	} else {
+		e = fmt.Errorf("we got 200 responce but have false in status returned struct %+v", r)
+	}
+	return
+}
+
 // SendMessage Sends message, we take interface{} you can send any struct as message but we recomend to use SendMessageType type as a message to avoid error responce from Telegram API will return Message type
 func (t *TbBot) SendMessage(message interface{}) (m *Message, e error) {
+	return t.SendMessageContext(context.Background(), message)
+}
+
+// SendMessageContext Same as SendMessage, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendMessageContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "sendMessage")
+	resp, e := t.sendPost(ctx, message, "sendMessage")
 	if e != nil {
 		return
 	}
@@ -76,11 +250,16 @@ func (t *TbBot) SendMessage(message interface{}) (m *Message, e error) {
 
 // ForwardMessage Use this method to forward messages of any kind. On success, the sent Message is returned. Accepts type ForwardMessageType call it from tbb library
 func (t *TbBot) ForwardMessage(message interface{}) (m *Message, e error) {
+	return t.ForwardMessageContext(context.Background(), message)
+}
+
+// ForwardMessageContext Same as ForwardMessage, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) ForwardMessageContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "forwardMessage")
+	resp, e := t.sendPost(ctx, message, "forwardMessage")
 	if e != nil {
 		return
 	}
@@ -99,11 +278,16 @@ func (t *TbBot) ForwardMessage(message interface{}) (m *Message, e error) {
 
 // CopyMessage Use this method to copy messages of any kind. The method is analogous to the method forwardMessage, but the copied message doesn't have a link to the original message. Returns the MessageId of the sent message on success. Accept CopyMessageType struct, but can also take any interface.
 func (t *TbBot) CopyMessage(message interface{}) (m *Message, e error) {
+	return t.CopyMessageContext(context.Background(), message)
+}
+
+// CopyMessageContext Same as CopyMessage, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) CopyMessageContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "copyMessage")
+	resp, e := t.sendPost(ctx, message, "copyMessage")
 	if e != nil {
 		return
 	}
@@ -120,23 +304,20 @@ func (t *TbBot) CopyMessage(message interface{}) (m *Message, e error) {
 	return
 }
 
-// SendPhoto Use this method to send photos. On success, the sent Message is returned. Send nil if using ID of a file or file not using direct file to send
-func (t *TbBot) SendPhoto(message interface{}, file *os.File) (m *Message, e error) {
+// SendPhoto Use this method to send photos. On success, the sent Message is returned. file can be FileID, FileURL, FilePath or FileReader, send nil if file is already set in message
+func (t *TbBot) SendPhoto(message interface{}, file *InputFile) (m *Message, e error) {
+	return t.SendPhotoContext(context.Background(), message, file)
+}
+
+// SendPhotoContext Same as SendPhoto, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendPhotoContext(ctx context.Context, message interface{}, file *InputFile) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendPhoto", "photo", message)
-		if e != nil {
-			return nil, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "sendPhoto")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "sendPhoto", "photo", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -152,22 +333,19 @@ func (t *TbBot) SendPhoto(message interface{}, file *os.File) (m *Message, e err
 }
 
 // SendAudio Use this method to send audio files, if you want Telegram clients to display them in the music player. Your audio must be in the .MP3 or .M4A format. On success, the sent Message is returned. Bots can currently send audio files of up to 50 MB in size, this limit may be changed in the future. For sending voice messages, use the sendVoice method instead. Accepts SendAudioType type as a struct, but can accept interface if needed
-func (t *TbBot) SendAudio(message interface{}, file *os.File) (m *Message, e error) {
+func (t *TbBot) SendAudio(message interface{}, file *InputFile) (m *Message, e error) {
+	return t.SendAudioContext(context.Background(), message, file)
+}
+
+// SendAudioContext Same as SendAudio, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendAudioContext(ctx context.Context, message interface{}, file *InputFile) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendAudio", "audio", message)
-		if e != nil {
-			return nil, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "sendAudio")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "sendAudio", "audio", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -183,22 +361,19 @@ func (t *TbBot) SendAudio(message interface{}, file *os.File) (m *Message, e err
 }
 
 // SendDocument Use this method to send general files. On success, the sent Message is returned. Bots can currently send files of any type of up to 50 MB in size, this limit may be changed in the future. Accepts SendDocumentType as message. but can accept interface if needed
-func (t *TbBot) SendDocument(message interface{}, file *os.File) (m *Message, e error) {
+func (t *TbBot) SendDocument(message interface{}, file *InputFile) (m *Message, e error) {
+	return t.SendDocumentContext(context.Background(), message, file)
+}
+
+// SendDocumentContext Same as SendDocument, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendDocumentContext(ctx context.Context, message interface{}, file *InputFile) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendDocument", "document", message)
-		if e != nil {
-			return nil, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "sendDocument")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "sendDocument", "document", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -214,22 +389,19 @@ func (t *TbBot) SendDocument(message interface{}, file *os.File) (m *Message, e
 }
 
 // SendVideo Use this method to send video files, Telegram clients support mp4 videos (other formats may be sent as Document). On success, the sent Message is returned. Bots can currently send video files of up to 50 MB in size, this limit may be changed in the future. Accepts SendVideoType as a struct. but can accept interface if needed
-func (t *TbBot) SendVideo(message interface{}, file *os.File) (m *Message, e error) {
+func (t *TbBot) SendVideo(message interface{}, file *InputFile) (m *Message, e error) {
+	return t.SendVideoContext(context.Background(), message, file)
+}
+
+// SendVideoContext Same as SendVideo, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendVideoContext(ctx context.Context, message interface{}, file *InputFile) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendVideo", "video", message)
-		if e != nil {
-			return nil, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "sendVideo")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "sendVideo", "video", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -245,22 +417,19 @@ func (t *TbBot) SendVideo(message interface{}, file *os.File) (m *Message, e err
 }
 
 // SendAnimation Use this method to send animation files (GIF or H.264/MPEG-4 AVC video without sound). On success, the sent Message is returned. Bots can currently send animation files of up to 50 MB in size, this limit may be changed in the future. Accepts SendAnimationType struct, but can accept interface if needed
-func (t *TbBot) SendAnimation(message interface{}, file *os.File) (m *Message, e error) {
+func (t *TbBot) SendAnimation(message interface{}, file *InputFile) (m *Message, e error) {
+	return t.SendAnimationContext(context.Background(), message, file)
+}
+
+// SendAnimationContext Same as SendAnimation, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendAnimationContext(ctx context.Context, message interface{}, file *InputFile) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendAnimation", "animation", message)
-		if e != nil {
-			return nil, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "sendAnimation")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "sendAnimation", "animation", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -276,22 +445,19 @@ func (t *TbBot) SendAnimation(message interface{}, file *os.File) (m *Message, e
 }
 
 // SendVoice Use this method to send audio files, if you want Telegram clients to display the file as a playable voice message. For this to work, your audio must be in an .OGG file encoded with OPUS (other formats may be sent as Audio or Document). On success, the sent Message is returned. Bots can currently send voice messages of up to 50 MB in size, this limit may be changed in the future. Accepts SendVoiceType struct, but can accept interface if needed
-func (t *TbBot) SendVoice(message interface{}, file *os.File) (m *Message, e error) {
+func (t *TbBot) SendVoice(message interface{}, file *InputFile) (m *Message, e error) {
+	return t.SendVoiceContext(context.Background(), message, file)
+}
+
+// SendVoiceContext Same as SendVoice, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendVoiceContext(ctx context.Context, message interface{}, file *InputFile) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendVoice", "voice", message)
-		if e != nil {
-			return nil, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "sendVoice")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "sendVoice", "voice", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -307,22 +473,19 @@ func (t *TbBot) SendVoice(message interface{}, file *os.File) (m *Message, e err
 }
 
 // SendVideoNote As of v.4.0, Telegram clients support rounded square mp4 videos of up to 1 minute long. Use this method to send video messages. On success, the sent Message is returned. Accepts SendVideoNoteType struct, but can accept interface if needed
-func (t *TbBot) SendVideoNote(message interface{}, file *os.File) (m *Message, e error) {
+func (t *TbBot) SendVideoNote(message interface{}, file *InputFile) (m *Message, e error) {
+	return t.SendVideoNoteContext(context.Background(), message, file)
+}
+
+// SendVideoNoteContext Same as SendVideoNote, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendVideoNoteContext(ctx context.Context, message interface{}, file *InputFile) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendVideoNote", "videonote", message)
-		if e != nil {
-			return nil, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "sendVideoNote")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "sendVideoNote", "video_note", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -337,31 +500,47 @@ func (t *TbBot) SendVideoNote(message interface{}, file *os.File) (m *Message, e
 	return
 }
 
-// SendMediaGroup Use this method to send a group of photos, videos, documents or audios as an album. Documents and audio files can be only grouped in an album with messages of the same type. On success, an array of Messages that were sent is returned. Accepts SendMediaGroupType struct, but can accept interface if needed
-func (t *TbBot) SendMediaGroup(message interface{}, file *os.File) (m *Message, e error) {
+// SendMediaGroup Use this method to send a group of photos, videos, documents or audios as an album. Documents and audio files can be only grouped in an album with messages of the same type. On success, an array of Messages that were sent is returned. Media items can have *InputFile in Media and Thumb fields, uploaded files are sent in one request. Accepts SendMediaGroupType struct, but can accept interface if needed
+func (t *TbBot) SendMediaGroup(message interface{}) (m []Message, e error) {
+	return t.SendMediaGroupContext(context.Background(), message)
+}
+
+// SendMediaGroupContext Same as SendMediaGroup, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendMediaGroupContext(ctx context.Context, message interface{}) (m []Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
 	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "sendMediaGroup", "mediagroup", message)
+	d, files, e := attachMedia(paramOf(message, "media"))
+	if e != nil {
+		return nil, e
+	}
+	if len(files) > 0 {
+		// Media is sent as JSON-serialized array with attach:// references to uploaded files
+		p, e := withParam(message, "media", string(d))
+		if e != nil {
+			return nil, e
+		}
+		resp, e = t.uploadFiles(ctx, "sendMediaGroup", p, files)
 		if e != nil {
 			return nil, e
 		}
 	} else {
-		resp, e = t.sendPost(message, "sendMediaGroup")
+		resp, e = t.sendPost(ctx, message, "sendMediaGroup")
 		if e != nil {
 			return
 		}
 	}
-	var r responce
+	var r struct {
+		IsOk bool      `json:"ok,omitempty"`
+		Type []Message `json:"result,omitempty"`
+	}
 	if e = json.Unmarshal(resp, &r); e != nil {
 		return
 	}
 	if r.IsOk {
-		m = &r.Type
-		return
+		m = r.Type
 	} else {
 		e = fmt.Errorf("we got 200 responce but have false in status returned struct %+v", r)
 	}
@@ -370,11 +549,16 @@ func (t *TbBot) SendMediaGroup(message interface{}, file *os.File) (m *Message,
 
 // SendLocation Use this method to send point on the map. On success, the sent Message is returned. Accepts SendLocationType struct, but can accept interface if needed
 func (t *TbBot) SendLocation(message interface{}) (m *Message, e error) {
+	return t.SendLocationContext(context.Background(), message)
+}
+
+// SendLocationContext Same as SendLocation, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendLocationContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "sendLocation")
+	resp, e := t.sendPost(ctx, message, "sendLocation")
 	if e != nil {
 		return
 	}
@@ -393,11 +577,16 @@ func (t *TbBot) SendLocation(message interface{}) (m *Message, e error) {
 
 // EditMessageLiveLocation Use this method to edit live location messages. A location can be edited until its live_period expires or editing is explicitly disabled by a call to stopMessageLiveLocation. On success, if the edited message is not an inline message, the edited Message is returned, otherwise True is returned. Accepts EditMessageLiveLocationType struct, but can accept interface if needed.
 func (t *TbBot) EditMessageLiveLocation(message interface{}) (m *Message, e error) {
+	return t.EditMessageLiveLocationContext(context.Background(), message)
+}
+
+// EditMessageLiveLocationContext Same as EditMessageLiveLocation, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) EditMessageLiveLocationContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "editMessageLiveLocation")
+	resp, e := t.sendPost(ctx, message, "editMessageLiveLocation")
 	if e != nil {
 		return
 	}
@@ -416,11 +605,16 @@ func (t *TbBot) EditMessageLiveLocation(message interface{}) (m *Message, e erro
 
 // StopMessageLiveLocation Use this method to stop updating a live location message before live_period expires. On success, if the message was sent by the bot, the sent Message is returned, otherwise True is returned. Accepts StopMessageLiveLocationType struct, but can accept interface if needed.
 func (t *TbBot) StopMessageLiveLocation(message interface{}) (m *Message, e error) {
+	return t.StopMessageLiveLocationContext(context.Background(), message)
+}
+
+// StopMessageLiveLocationContext Same as StopMessageLiveLocation, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) StopMessageLiveLocationContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "stopMessageLiveLocation")
+	resp, e := t.sendPost(ctx, message, "stopMessageLiveLocation")
 	if e != nil {
 		return
 	}
@@ -439,11 +633,16 @@ func (t *TbBot) StopMessageLiveLocation(message interface{}) (m *Message, e erro
 
 // SendVenue Use this method to send information about a venue. On success, the sent Message is returned. Accepts SendVenue struct, but can accept interface if needed.
 func (t *TbBot) SendVenue(message interface{}) (m *Message, e error) {
+	return t.SendVenueContext(context.Background(), message)
+}
+
+// SendVenueContext Same as SendVenue, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendVenueContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "sendVenue")
+	resp, e := t.sendPost(ctx, message, "sendVenue")
 	if e != nil {
 		return
 	}
@@ -462,11 +661,16 @@ func (t *TbBot) SendVenue(message interface{}) (m *Message, e error) {
 
 // SendContact Use this method to send phone contacts. On success, the sent Message is returned. Accepts SendContactType struct, but can accept interface if needed.
 func (t *TbBot) SendContact(message interface{}) (m *Message, e error) {
+	return t.SendContactContext(context.Background(), message)
+}
+
+// SendContactContext Same as SendContact, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendContactContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "sendContact")
+	resp, e := t.sendPost(ctx, message, "sendContact")
 	if e != nil {
 		return
 	}
@@ -485,11 +689,16 @@ func (t *TbBot) SendContact(message interface{}) (m *Message, e error) {
 
 // SendPoll Use this method to send a native poll. On success, the sent Message is returned. Accepts SendPollType struct, but can accept interface if needed.
 func (t *TbBot) SendPoll(message interface{}) (m *Message, e error) {
+	return t.SendPollContext(context.Background(), message)
+}
+
+// SendPollContext Same as SendPoll, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendPollContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "sendPoll")
+	resp, e := t.sendPost(ctx, message, "sendPoll")
 	if e != nil {
 		return
 	}
@@ -508,11 +717,16 @@ func (t *TbBot) SendPoll(message interface{}) (m *Message, e error) {
 
 // SendDice Use this method to send an animated emoji that will display a random value. On success, the sent Message is returned. Accepts SendDiceType struct, but can accept interface if needed.
 func (t *TbBot) SendDice(message interface{}) (m *Message, e error) {
+	return t.SendDiceContext(context.Background(), message)
+}
+
+// SendDiceContext Same as SendDice, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendDiceContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "sendDice")
+	resp, e := t.sendPost(ctx, message, "sendDice")
 	if e != nil {
 		return
 	}
@@ -531,11 +745,16 @@ func (t *TbBot) SendDice(message interface{}) (m *Message, e error) {
 
 // SendChatAction Use this method when you need to tell the user that something is happening on the bot's side. The status is set for 5 seconds or less (when a message arrives from your bot, Telegram clients clear its typing status). Returns True on success. Accepts SendChatActionType struct, but can accept interface if needed.
 func (t *TbBot) SendChatAction(message interface{}) (m *Message, e error) {
+	return t.SendChatActionContext(context.Background(), message)
+}
+
+// SendChatActionContext Same as SendChatAction, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SendChatActionContext(ctx context.Context, message interface{}) (m *Message, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "sendChatAction")
+	resp, e := t.sendPost(ctx, message, "sendChatAction")
 	if e != nil {
 		return
 	}
@@ -554,11 +773,16 @@ func (t *TbBot) SendChatAction(message interface{}) (m *Message, e error) {
 
 // GetUserProfilePhotos Use this method to get a list of profile pictures for a user. Returns a UserProfilePhotos object.
 func (t *TbBot) GetUserProfilePhotos(message interface{}) (m *UserProfilePhotos, e error) {
+	return t.GetUserProfilePhotosContext(context.Background(), message)
+}
+
+// GetUserProfilePhotosContext Same as GetUserProfilePhotos, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetUserProfilePhotosContext(ctx context.Context, message interface{}) (m *UserProfilePhotos, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "getUserProfilePhotos")
+	resp, e := t.sendPost(ctx, message, "getUserProfilePhotos")
 	if e != nil {
 		return
 	}
@@ -582,11 +806,16 @@ func (t *TbBot) GetUserProfilePhotos(message interface{}) (m *UserProfilePhotos,
 // GetFile Use this method to get basic info about a file and prepare it for downloading. For the moment, bots can download files of up to 20MB in size. On success, a File object is returned. The file can then be downloaded via the link https://api.telegram.org/file/bot<token>/<file_path>, where <file_path> is taken from the response. It is guaranteed that the link will be valid for at least 1 hour. When the link expires, a new one can be requested by calling getFile again.
 // Note: This function may not preserve the original file name and MIME type. You should save the file's MIME type and name (if available) when the File object is received.
 func (t *TbBot) GetFile(message interface{}) (m *File, e error) {
+	return t.GetFileContext(context.Background(), message)
+}
+
+// GetFileContext Same as GetFile, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetFileContext(ctx context.Context, message interface{}) (m *File, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "getFile")
+	resp, e := t.sendPost(ctx, message, "getFile")
 	if e != nil {
 		return
 	}
@@ -607,13 +836,31 @@ func (t *TbBot) GetFile(message interface{}) (m *File, e error) {
 	return
 }
 
+// FileLink Returns link to download file returned by GetFile
+// Local Bot API server started with --local option returns absolute file path, for such files file:// url is returned
+func (t *TbBot) FileLink(f *File) (string, error) {
+	if f == nil || f.FilePath == "" {
+		return "", fmt.Errorf("file has no file_path, call GetFile to get it")
+	}
+	if filepath.IsAbs(f.FilePath) {
+		u := url.URL{Scheme: "file", Path: filepath.ToSlash(f.FilePath)}
+		return u.String(), nil
+	}
+	return t.fileURL + "/bot" + t.token + "/" + f.FilePath, nil
+}
+
 // KickChatMember Use this method to kick a user from a group, a supergroup or a channel. In the case of supergroups and channels, the user will not be able to return to the chat on their own using invite links, etc., unless unbanned first. The bot must be an administrator in the chat for this to work and must have the appropriate admin rights. Returns True on success. Accepts KickChatMemberType or any interface
 func (t *TbBot) KickChatMember(message interface{}) (m bool, e error) {
+	return t.KickChatMemberContext(context.Background(), message)
+}
+
+// KickChatMemberContext Same as KickChatMember, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) KickChatMemberContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "kickChatMember")
+	resp, e := t.sendPost(ctx, message, "kickChatMember")
 	if e != nil {
 		return
 	}
@@ -634,11 +881,16 @@ func (t *TbBot) KickChatMember(message interface{}) (m bool, e error) {
 	only_if_banned 	Boolean 			Optional 	Do nothing if the user is not banned
 */
 func (t *TbBot) UnbanChatMember(message interface{}) (m bool, e error) {
+	return t.UnbanChatMemberContext(context.Background(), message)
+}
+
+// UnbanChatMemberContext Same as UnbanChatMember, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) UnbanChatMemberContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "unbanChatMember")
+	resp, e := t.sendPost(ctx, message, "unbanChatMember")
 	if e != nil {
 		return
 	}
@@ -660,11 +912,16 @@ func (t *TbBot) UnbanChatMember(message interface{}) (m bool, e error) {
 	until_date 		Integer 			Optional 	Date when restrictions will be lifted for the user, unix time. If user is restricted for more than 366 days or less than 30 seconds from the current time, they are considered to be restricted forever
 */
 func (t *TbBot) RestrictChatMember(message interface{}) (m bool, e error) {
+	return t.RestrictChatMemberContext(context.Background(), message)
+}
+
+// RestrictChatMemberContext Same as RestrictChatMember, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) RestrictChatMemberContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "restrictChatMember")
+	resp, e := t.sendPost(ctx, message, "restrictChatMember")
 	if e != nil {
 		return
 	}
@@ -679,11 +936,16 @@ func (t *TbBot) RestrictChatMember(message interface{}) (m bool, e error) {
 
 // PromoteChatMember Use this method to promote or demote a user in a supergroup or a channel. The bot must be an administrator in the chat for this to work and must have the appropriate admin rights. Pass False for all boolean parameters to demote a user. Returns True on success. Accepts PromoteChatMemberType struct but also can take interface
 func (t *TbBot) PromoteChatMember(message interface{}) (m bool, e error) {
+	return t.PromoteChatMemberContext(context.Background(), message)
+}
+
+// PromoteChatMemberContext Same as PromoteChatMember, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) PromoteChatMemberContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "promoteChatMember")
+	resp, e := t.sendPost(ctx, message, "promoteChatMember")
 	if e != nil {
 		return
 	}
@@ -704,11 +966,16 @@ func (t *TbBot) PromoteChatMember(message interface{}) (m bool, e error) {
 	custom_title 	String 				Yes 		New custom title for the administrator; 0-16 characters, emoji are not allowed
 */
 func (t *TbBot) SetChatAdministratorCustomTitle(message interface{}) (m bool, e error) {
+	return t.SetChatAdministratorCustomTitleContext(context.Background(), message)
+}
+
+// SetChatAdministratorCustomTitleContext Same as SetChatAdministratorCustomTitle, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SetChatAdministratorCustomTitleContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "setChatAdministratorCustomTitle")
+	resp, e := t.sendPost(ctx, message, "setChatAdministratorCustomTitle")
 	if e != nil {
 		return
 	}
@@ -723,11 +990,16 @@ func (t *TbBot) SetChatAdministratorCustomTitle(message interface{}) (m bool, e
 
 // SetChatPermissions Use this method to set default chat permissions for all members. The bot must be an administrator in the group or a supergroup for this to work and must have the can_restrict_members admin rights. Returns True on success. Accepts SetChatPermissionsType struct, but also can take an interface.
 func (t *TbBot) SetChatPermissions(message interface{}) (m bool, e error) {
+	return t.SetChatPermissionsContext(context.Background(), message)
+}
+
+// SetChatPermissionsContext Same as SetChatPermissions, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SetChatPermissionsContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "setChatPermissions")
+	resp, e := t.sendPost(ctx, message, "setChatPermissions")
 	if e != nil {
 		return
 	}
@@ -746,11 +1018,16 @@ func (t *TbBot) SetChatPermissions(message interface{}) (m bool, e error) {
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 */
 func (t *TbBot) ExportChatInviteLink(message interface{}) (m interface{}, e error) {
+	return t.ExportChatInviteLinkContext(context.Background(), message)
+}
+
+// ExportChatInviteLinkContext Same as ExportChatInviteLink, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) ExportChatInviteLinkContext(ctx context.Context, message interface{}) (m interface{}, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "exportChatInviteLink")
+	resp, e := t.sendPost(ctx, message, "exportChatInviteLink")
 	if e != nil {
 		return
 	}
@@ -779,11 +1056,16 @@ func (t *TbBot) ExportChatInviteLink(message interface{}) (m interface{}, e erro
 	member_limit 	Integer 			Optional 	Maximum number of users that can be members of the chat simultaneously after joining the chat via this invite link; 1-99999
 */
 func (t *TbBot) CreateChatInviteLink(message interface{}) (m *ChatInviteLinkType, e error) {
+	return t.CreateChatInviteLinkContext(context.Background(), message)
+}
+
+// CreateChatInviteLinkContext Same as CreateChatInviteLink, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) CreateChatInviteLinkContext(ctx context.Context, message interface{}) (m *ChatInviteLinkType, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "createChatInviteLink")
+	resp, e := t.sendPost(ctx, message, "createChatInviteLink")
 	if e != nil {
 		return
 	}
@@ -813,11 +1095,16 @@ func (t *TbBot) CreateChatInviteLink(message interface{}) (m *ChatInviteLinkType
 	member_limit 	Integer 			Optional 	Maximum number of users that can be members of the chat simultaneously after joining the chat via this invite link; 1-99999
 */
 func (t *TbBot) EditChatInviteLink(message interface{}) (m *ChatInviteLinkType, e error) {
+	return t.EditChatInviteLinkContext(context.Background(), message)
+}
+
+// EditChatInviteLinkContext Same as EditChatInviteLink, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) EditChatInviteLinkContext(ctx context.Context, message interface{}) (m *ChatInviteLinkType, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "editChatInviteLink")
+	resp, e := t.sendPost(ctx, message, "editChatInviteLink")
 	if e != nil {
 		return
 	}
@@ -845,11 +1132,16 @@ func (t *TbBot) EditChatInviteLink(message interface{}) (m *ChatInviteLinkType,
 	invite_link 	String 				Yes 		The invite link to revoke
 */
 func (t *TbBot) RevokeChatInviteLink(message interface{}) (m *ChatInviteLinkType, e error) {
+	return t.RevokeChatInviteLinkContext(context.Background(), message)
+}
+
+// RevokeChatInviteLinkContext Same as RevokeChatInviteLink, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) RevokeChatInviteLinkContext(ctx context.Context, message interface{}) (m *ChatInviteLinkType, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "revokeChatInviteLink")
+	resp, e := t.sendPost(ctx, message, "revokeChatInviteLink")
 	if e != nil {
 		return
 	}
@@ -876,22 +1168,19 @@ func (t *TbBot) RevokeChatInviteLink(message interface{}) (m *ChatInviteLinkType
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 	photo 		InputFile 			Yes 		New chat photo, uploaded using multipart/form-data
 */
-func (t *TbBot) SetChatPhoto(message interface{}, file *os.File) (m bool, e error) {
+func (t *TbBot) SetChatPhoto(message interface{}, file *InputFile) (m bool, e error) {
+	return t.SetChatPhotoContext(context.Background(), message, file)
+}
+
+// SetChatPhotoContext Same as SetChatPhoto, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SetChatPhotoContext(ctx context.Context, message interface{}, file *InputFile) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	var resp []byte
-	if file != nil {
-		resp, e = t.uploadFile(file, "setChatPhoto", "photo", message)
-		if e != nil {
-			return false, e
-		}
-	} else {
-		resp, e = t.sendPost(message, "setChatPhoto")
-		if e != nil {
-			return
-		}
+	resp, e := t.sendFile(ctx, "setChatPhoto", "photo", message, file)
+	if e != nil {
+		return
 	}
 	var r responce
 	if e = json.Unmarshal(resp, &r); e != nil {
@@ -912,11 +1201,16 @@ func (t *TbBot) SetChatPhoto(message interface{}, file *os.File) (m bool, e erro
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 */
 func (t *TbBot) DeleteChatPhoto(message interface{}) (m bool, e error) {
+	return t.DeleteChatPhotoContext(context.Background(), message)
+}
+
+// DeleteChatPhotoContext Same as DeleteChatPhoto, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) DeleteChatPhotoContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "deleteChatPhoto")
+	resp, e := t.sendPost(ctx, message, "deleteChatPhoto")
 	if e != nil {
 		return
 	}
@@ -936,11 +1230,16 @@ func (t *TbBot) DeleteChatPhoto(message interface{}) (m bool, e error) {
 	title 		String 				Yes 	New chat title, 1-255 characters
 */
 func (t *TbBot) SetChatTitle(message interface{}) (m bool, e error) {
+	return t.SetChatTitleContext(context.Background(), message)
+}
+
+// SetChatTitleContext Same as SetChatTitle, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SetChatTitleContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "setChatTitle")
+	resp, e := t.sendPost(ctx, message, "setChatTitle")
 	if e != nil {
 		return
 	}
@@ -960,11 +1259,16 @@ func (t *TbBot) SetChatTitle(message interface{}) (m bool, e error) {
 	description 	String 				Optional 	New chat description, 0-255 characters
 */
 func (t *TbBot) SetChatDescription(message interface{}) (m bool, e error) {
+	return t.SetChatDescriptionContext(context.Background(), message)
+}
+
+// SetChatDescriptionContext Same as SetChatDescription, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SetChatDescriptionContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "setChatDescription")
+	resp, e := t.sendPost(ctx, message, "setChatDescription")
 	if e != nil {
 		return
 	}
@@ -985,11 +1289,16 @@ func (t *TbBot) SetChatDescription(message interface{}) (m bool, e error) {
 	disable_notification 			Boolean 	Optional 	Pass True, if it is not necessary to send a notification to all chat members about the new pinned message. Notifications are always disabled in channels and private chats.
 */
 func (t *TbBot) PinChatMessage(message interface{}) (m bool, e error) {
+	return t.PinChatMessageContext(context.Background(), message)
+}
+
+// PinChatMessageContext Same as PinChatMessage, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) PinChatMessageContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "pinChatMessage")
+	resp, e := t.sendPost(ctx, message, "pinChatMessage")
 	if e != nil {
 		return
 	}
@@ -1009,11 +1318,16 @@ func (t *TbBot) PinChatMessage(message interface{}) (m bool, e error) {
 	message_id 	Integer 			Optional 	Identifier of a message to unpin. If not specified, the most recent pinned message (by sending date) will be unpinned.
 */
 func (t *TbBot) UnpinChatMessage(message interface{}) (m bool, e error) {
+	return t.UnpinChatMessageContext(context.Background(), message)
+}
+
+// UnpinChatMessageContext Same as UnpinChatMessage, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) UnpinChatMessageContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "unpinChatMessage")
+	resp, e := t.sendPost(ctx, message, "unpinChatMessage")
 	if e != nil {
 		return
 	}
@@ -1032,11 +1346,16 @@ func (t *TbBot) UnpinChatMessage(message interface{}) (m bool, e error) {
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 */
 func (t *TbBot) UnpinAllChatMessages(message interface{}) (m bool, e error) {
+	return t.UnpinAllChatMessagesContext(context.Background(), message)
+}
+
+// UnpinAllChatMessagesContext Same as UnpinAllChatMessages, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) UnpinAllChatMessagesContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "unpinAllChatMessages")
+	resp, e := t.sendPost(ctx, message, "unpinAllChatMessages")
 	if e != nil {
 		return
 	}
@@ -1055,11 +1374,16 @@ func (t *TbBot) UnpinAllChatMessages(message interface{}) (m bool, e error) {
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target supergroup or channel (in the format @channelusername)
 */
 func (t *TbBot) LeaveChat(message interface{}) (m bool, e error) {
+	return t.LeaveChatContext(context.Background(), message)
+}
+
+// LeaveChatContext Same as LeaveChat, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) LeaveChatContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "leaveChat")
+	resp, e := t.sendPost(ctx, message, "leaveChat")
 	if e != nil {
 		return
 	}
@@ -1078,11 +1402,16 @@ func (t *TbBot) LeaveChat(message interface{}) (m bool, e error) {
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target supergroup or channel (in the format @channelusername)
 */
 func (t *TbBot) GetChat(message interface{}) (m bool, e error) {
+	return t.GetChatContext(context.Background(), message)
+}
+
+// GetChatContext Same as GetChat, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetChatContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "getChat")
+	resp, e := t.sendPost(ctx, message, "getChat")
 	if e != nil {
 		return
 	}
@@ -1101,11 +1430,16 @@ func (t *TbBot) GetChat(message interface{}) (m bool, e error) {
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target supergroup or channel (in the format @channelusername)
 */
 func (t *TbBot) GetChatAdministrators(message interface{}) (m *[]ChatMember, e error) {
+	return t.GetChatAdministratorsContext(context.Background(), message)
+}
+
+// GetChatAdministratorsContext Same as GetChatAdministrators, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetChatAdministratorsContext(ctx context.Context, message interface{}) (m *[]ChatMember, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "getChatAdministrators")
+	resp, e := t.sendPost(ctx, message, "getChatAdministrators")
 	if e != nil {
 		return
 	}
@@ -1130,11 +1464,16 @@ func (t *TbBot) GetChatAdministrators(message interface{}) (m *[]ChatMember, e e
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target supergroup or channel (in the format @channelusername)
 */
 func (t *TbBot) GetChatMembersCount(message interface{}) (m int, e error) {
+	return t.GetChatMembersCountContext(context.Background(), message)
+}
+
+// GetChatMembersCountContext Same as GetChatMembersCount, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetChatMembersCountContext(ctx context.Context, message interface{}) (m int, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "getChatMembersCount")
+	resp, e := t.sendPost(ctx, message, "getChatMembersCount")
 	if e != nil {
 		return
 	}
@@ -1161,11 +1500,16 @@ func (t *TbBot) GetChatMembersCount(message interface{}) (m int, e error) {
 	user_id 	Integer 			Yes 		Unique identifier of the target user
 */
 func (t *TbBot) GetChatMember(message interface{}) (m *ChatMember, e error) {
+	return t.GetChatMemberContext(context.Background(), message)
+}
+
+// GetChatMemberContext Same as GetChatMember, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) GetChatMemberContext(ctx context.Context, message interface{}) (m *ChatMember, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "getChatMember")
+	resp, e := t.sendPost(ctx, message, "getChatMember")
 	if e != nil {
 		return
 	}
@@ -1192,11 +1536,16 @@ func (t *TbBot) GetChatMember(message interface{}) (m *ChatMember, e error) {
 	sticker_set_name 	String 				Yes 		Name of the sticker set to be set as the group sticker set
 */
 func (t *TbBot) SetChatStickerSet(message interface{}) (m bool, e error) {
+	return t.SetChatStickerSetContext(context.Background(), message)
+}
+
+// SetChatStickerSetContext Same as SetChatStickerSet, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) SetChatStickerSetContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "setChatStickerSet")
+	resp, e := t.sendPost(ctx, message, "setChatStickerSet")
 	if e != nil {
 		return
 	}
@@ -1215,11 +1564,16 @@ func (t *TbBot) SetChatStickerSet(message interface{}) (m bool, e error) {
 	chat_id 	Integer or String 	Yes 		Unique identifier for the target chat or username of the target supergroup (in the format @supergroupusername)
 */
 func (t *TbBot) DeleteChatStickerSet(message interface{}) (m bool, e error) {
+	return t.DeleteChatStickerSetContext(context.Background(), message)
+}
+
+// DeleteChatStickerSetContext Same as DeleteChatStickerSet, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) DeleteChatStickerSetContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "deleteChatStickerSet")
+	resp, e := t.sendPost(ctx, message, "deleteChatStickerSet")
 	if e != nil {
 		return
 	}
@@ -1235,7 +1589,6 @@ func (t *TbBot) DeleteChatStickerSet(message interface{}) (m bool, e error) {
 // TODO
 /*
 	Add methods:
-		- answerCallbackQuery
 		- setMyCommands
 		- getMyCommands
 */
@@ -1255,11 +1608,16 @@ func (t *TbBot) DeleteChatStickerSet(message interface{}) (m bool, e error) {
 	reply_markup 				InlineKeyboardMarkup 		Optional 	A JSON-serialized object for an inline keyboard.
 */
 func (t *TbBot) EditMessageText(message interface{}) (m bool, e error) {
+	return t.EditMessageTextContext(context.Background(), message)
+}
+
+// EditMessageTextContext Same as EditMessageText, but uses ctx to cancel request or set it's deadline
+func (t *TbBot) EditMessageTextContext(ctx context.Context, message interface{}) (m bool, e error) {
 	if message == nil {
 		e = fmt.Errorf("message can't be nil")
 		return
 	}
-	resp, e := t.sendPost(message, "editMessageText")
+	resp, e := t.sendPost(ctx, message, "editMessageText")
 	if e != nil {
 		return
 	}
@@ -1272,6 +1630,45 @@ func (t *TbBot) EditMessageText(message interface{}) (m bool, e error) {
 	return
 }
 
+// AnswerCallbackQuery Use this method to send answers to callback queries sent from inline keyboards. The answer will be displayed to the user as a notification at the top of the chat screen or as an alert. On success, True is returned. Accepts AnswerCallbackQueryType or any interface
+/*
+	Parameter 			Type 		Required 	Description
+	callback_query_id 	String 		Yes 		Unique identifier for the query to be answered
+	text 				String 		Optional 	Text of the notification. If not specified, nothing will be shown to the user, 0-200 characters
+	show_alert 			Boolean 	Optional 	If true, an alert will be shown by the client instead of a notification at the top of the chat screen. Defaults to false.
+	url 				String 		Optional 	URL that will be opened by the user's client
+	cache_time 			Integer 	Optional 	The maximum amount of time in seconds that the result of the callback query may be cached client-side. Defaults to 0.
+*/
+func (t *TbBot) AnswerCallbackQuery(message interface{}) (m bool, e error) {
+	return t.AnswerCallbackQueryContext(context.Background(), message)
+}
+
+// AnswerCallbackQueryContext Same as AnswerCallbackQuery, but uses ctx to cancel request or set it's deadline
+// If ctx is handler Context of the same callback query, dispatcher won't answer it again
+func (t *TbBot) AnswerCallbackQueryContext(ctx context.Context, message interface{}) (m bool, e error) {
+	if message == nil {
+		e = fmt.Errorf("message can't be nil")
+		return
+	}
+	if c, ok := ctx.(*Context); ok {
+		c.markAnswered(message)
+	}
+	resp, e := t.sendPost(ctx, message, "answerCallbackQuery")
+	if e != nil {
+		return
+	}
+	// Working with responce
+	var r struct {
+		IsOk bool `json:"ok,omitempty"`
+		Type bool `json:"result,omitempty"`
+	}
+	if e = json.Unmarshal(resp, &r); e != nil {
+		return
+	}
+	m = r.IsOk && r.Type
+	return
+}
+
 // TODO
 // Other functions
 
@@ -1279,114 +1676,288 @@ func (t *TbBot) EditMessageText(message interface{}) (m bool, e error) {
 
 // Additional functions -------------
 
-func (t *TbBot) sendGet(method string) ([]byte, error) {
-	req, e := http.NewRequest("GET", fmt.Sprintf(URL, t.token, method), nil)
-	if e != nil {
-		return nil, e
+func (t *TbBot) sendGet(ctx context.Context, method string) ([]byte, error) {
+	return t.invoke(ctx, &APICall{Method: method}, func(ctx context.Context, c *APICall) ([]byte, error) {
+		return t.do(ctx, t.timeout, func() (*http.Request, error) {
+			req, e := http.NewRequest("GET", t.endpoint(c.Method), nil)
+			if e != nil {
+				return nil, e
+			}
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		})
+	})
+}
+
+func (t *TbBot) sendPost(ctx context.Context, data interface{}, method string) ([]byte, error) {
+	return t.post(ctx, data, method, t.timeout)
+}
+
+// post Same as sendPost but every request attempt is limited by timeout instead of default one, zero means no limit
+func (t *TbBot) post(ctx context.Context, data interface{}, method string, timeout time.Duration) ([]byte, error) {
+	return t.invoke(ctx, &APICall{Method: method, Params: data}, func(ctx context.Context, c *APICall) ([]byte, error) {
+		mrsh, e := json.Marshal(c.Params)
+		if e != nil {
+			return nil, e
+		}
+		if e = t.limit.wait(ctx, c.Method, chatIDParam(mrsh)); e != nil {
+			return nil, e
+		}
+		return t.do(ctx, timeout, func() (*http.Request, error) {
+			req, e := http.NewRequest("POST", t.endpoint(c.Method), bytes.NewReader(mrsh))
+			if e != nil {
+				return nil, e
+			}
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		})
+	})
+}
+
+// uploadFile Uploads file with multipart/form-data, upload is not limited by default request timeout as big files can take long to send
+func (t *TbBot) uploadFile(ctx context.Context, file *os.File, method string, name string, message interface{}) ([]byte, error) {
+	if file == nil {
+		return nil, fmt.Errorf("can't upload file that don't exists")
 	}
-	req.Header.Set("Content-Type", "application/json")
-	r, e := t.client.Do(req)
+	return t.uploadFiles(ctx, method, message, []formFile{{name: name, file: FileReader(file.Name(), file)}})
+}
+
+// sendFile Sends message with file as name param, file can be nil if message fields already have files
+// If file cache is set, file is sent by cached file_id when possible
+func (t *TbBot) sendFile(ctx context.Context, method string, name string, message interface{}, file *InputFile) ([]byte, error) {
+	if t.fileCache != nil && cachedMethods[method] {
+		if file == nil {
+			file = inputFileOf(paramOf(message, name))
+		}
+		if file != nil && file.upload() {
+			return t.sendCached(ctx, method, name, message, file)
+		}
+	}
+	return t.sendFiles(ctx, method, name, message, file)
+}
+
+// sendFiles Sends message with file as name param, file replaces name param of message
+// Request is sent as multipart/form-data only if there are files to upload
+func (t *TbBot) sendFiles(ctx context.Context, method string, name string, message interface{}, file *InputFile) ([]byte, error) {
+	files := filesOf(message)
+	if file != nil {
+		p, e := withParam(message, name, file)
+		if e != nil {
+			return nil, e
+		}
+		message = p
+		for i := 0; i < len(files); i++ {
+			if files[i].name == name {
+				files = append(files[:i], files[i+1:]...)
+				i--
+			}
+		}
+		if file.upload() {
+			files = append(files, formFile{name: name, file: file})
+		}
+	}
+	if len(files) == 0 {
+		return t.sendPost(ctx, message, method)
+	}
+	return t.uploadFiles(ctx, method, message, files)
+}
+
+// withParam Returns message params with value set as name param
+func withParam(message interface{}, name string, value interface{}) (Params, error) {
+	d, e := json.Marshal(message)
 	if e != nil {
 		return nil, e
 	}
-	defer r.Body.Close()
-	if r.StatusCode != http.StatusOK {
-		e = fmt.Errorf("we got invalid status code responce, code responce is %d", r.StatusCode)
+	var raw map[string]json.RawMessage
+	if e = json.Unmarshal(d, &raw); e != nil {
 		return nil, e
 	}
-	d, e := ioutil.ReadAll(r.Body)
-	if e != nil {
-		return nil, e
+	p := make(Params, len(raw)+1)
+	for k, v := range raw {
+		p[k] = v
 	}
-	return d, nil
+	p[name] = value
+	return p, nil
 }
 
-func (t *TbBot) sendPost(data interface{}, method string) ([]byte, error) {
-	mrsh, e := json.Marshal(data)
-	if e != nil {
+// uploadFiles Sends message params and all files in one multipart/form-data request, *os.File readers are closed after upload
+// Files are validated before upload, *ValidationError is returned if they break upload limits
+func (t *TbBot) uploadFiles(ctx context.Context, method string, message interface{}, files []formFile) ([]byte, error) {
+	for _, f := range files {
+		defer f.file.close()
+	}
+	if e := t.uploads.validate(method, files); e != nil {
 		return nil, e
 	}
-	b := bytes.NewReader(mrsh)
-	req, e := http.NewRequest("POST", fmt.Sprintf(URL, t.token, method), b)
+	return t.invoke(ctx, &APICall{Method: method, Params: message}, func(ctx context.Context, c *APICall) ([]byte, error) {
+		return t.upload(ctx, c.Method, c.Params, files)
+	})
+}
+
+// upload Sends message params and files in one multipart/form-data request
+// Body is streamed through a pipe, so files of any size are never buffered in memory and readers of unknown size can be sent
+func (t *TbBot) upload(ctx context.Context, method string, message interface{}, files []formFile) ([]byte, error) {
+	// Adding message params, file params are sent as form files
+	msg, e := json.Marshal(message)
 	if e != nil {
 		return nil, e
 	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, e := t.client.Do(req)
+	fields, e := formFields(msg)
 	if e != nil {
 		return nil, e
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		e = fmt.Errorf("we got invalid status code responce, code responce is %d", resp.StatusCode)
-		return nil, e
+	for _, f := range files {
+		delete(fields, f.name)
 	}
-	d, e := ioutil.ReadAll(resp.Body)
-	if e != nil {
+	if e = t.limit.wait(ctx, method, chatIDParam(msg)); e != nil {
 		return nil, e
 	}
-	return d, nil
-}
 
-func (t *TbBot) uploadFile(file *os.File, method string, name string, message interface{}) ([]byte, error) {
-	if file == nil {
-		return nil, fmt.Errorf("can't upload file that don't exists")
-	}
-	defer file.Close()
-	buff := &bytes.Buffer{}
-	writer := multipart.NewWriter(buff)
+	// Setup request, body of every attempt is written by it's own goroutine and files are reopened or rewinded
+	// so upload can be retried. Writer of previous attempt is stopped before next one starts
+	var stop func()
+	defer func() {
+		if stop != nil {
+			stop()
+		}
+	}()
+	return t.do(ctx, 0, func() (*http.Request, error) {
+		if stop != nil {
+			stop()
+		}
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			pw.CloseWithError(writeForm(writer, fields, files))
+		}()
+		stop = func() {
+			pr.Close()
+			<-done
+		}
+		req, e := http.NewRequest("POST", t.endpoint(method), pr)
+		if e != nil {
+			return nil, e
+		}
+		req.Header.Add("Content-Type", writer.FormDataContentType())
+		return req, nil
+	})
+}
 
-	// Adding message params
-	msg, e := json.Marshal(message)
-	if e != nil {
-		return nil, e
-	}
-	var params map[string]interface{}
-	if e = json.Unmarshal(msg, &params); e != nil {
+// formFields Converts JSON params to form fields the same way telegram reads them from JSON request
+// Strings are sent as is, numbers and bools as JSON literals and objects or arrays like reply_markup as JSON-serialized values
+func formFields(msg []byte) (map[string]string, error) {
+	var params map[string]json.RawMessage
+	if e := json.Unmarshal(msg, &params); e != nil {
 		return nil, e
 	}
+	fields := make(map[string]string, len(params))
 	for k, v := range params {
-		var field string
-		switch d := v.(type) {
-		case float64, float32:
-			field = fmt.Sprintf("%f", d)
+		v = bytes.TrimSpace(v)
+		switch {
+		case len(v) == 0 || bytes.Equal(v, []byte("null")):
+			continue
+		case v[0] == '"':
+			var s string
+			if e := json.Unmarshal(v, &s); e != nil {
+				return nil, e
+			}
+			fields[k] = s
 		default:
-			field = fmt.Sprint(d)
+			fields[k] = string(v)
+		}
+	}
+	return fields, nil
+}
+
+// writeForm Writes fields and files content as multipart form, every file is opened only while it's written
+func writeForm(writer *multipart.Writer, fields map[string]string, files []formFile) error {
+	for k, v := range fields {
+		if e := writer.WriteField(k, v); e != nil {
+			return e
+		}
+	}
+	for _, f := range files {
+		part, e := writer.CreateFormFile(f.name, f.file.name)
+		if e != nil {
+			return e
+		}
+		r, e := f.file.open()
+		if e != nil {
+			return e
+		}
+		_, e = io.Copy(part, r)
+		r.Close()
+		if e != nil {
+			return e
+		}
+	}
+	return writer.Close()
+}
+
+// do Makes request created by newReq and checks telegram responce, each attempt is limited by timeout if ctx has no deadline
+// If request was rejected by flood control and retry policy is set, new request is created and sent again after retry_after delay
+func (t *TbBot) do(ctx context.Context, timeout time.Duration, newReq func() (*http.Request, error)) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, e := newReq()
+		if e != nil {
+			return nil, e
 		}
-		if e = writer.WriteField(k, field); e != nil {
+		actx, cancel := withTimeout(ctx, timeout)
+		d, e := roundTrip(t.client, req.WithContext(actx))
+		cancel()
+		if e == nil {
+			return d, nil
+		}
+		wait, ok := t.retry.delay(e, attempt)
+		if !ok {
+			return nil, e
+		}
+		if e = sleep(ctx, wait); e != nil {
 			return nil, e
 		}
 	}
+}
 
-	boundary := writer.Boundary()
-	contentType := "multipart/form-data; boundary=" + boundary
-	closeBoundary := fmt.Sprintf("\r\n--%s--\r\n", boundary)
-	closeBuffer := bytes.NewBufferString(closeBoundary)
-	fi, e := os.Stat(filepath.Base(file.Name()))
-	if e != nil {
-		return nil, e
+// endpoint Returns url of api method
+func (t *TbBot) endpoint(method string) string {
+	return t.apiURL + "/bot" + t.token + "/" + method
+}
+
+// withTimeout Adds timeout to ctx if it has no deadline
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
-	size := fi.Size()
-	if _, e := writer.CreateFormFile(name, filepath.Base(file.Name())); e != nil {
-		return nil, e
+	return context.WithTimeout(ctx, timeout)
+}
+
+// sleep Waits for duration d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	tm := time.NewTimer(d)
+	defer tm.Stop()
+	select {
+	case <-tm.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	// Setup request
-	req, e := http.NewRequest("POST", fmt.Sprintf(URL, t.token, method), nil)
+}
+
+// roundTrip Sends request and returns responce body if telegram marked it as ok
+func roundTrip(cli *http.Client, req *http.Request) ([]byte, error) {
+	resp, e := cli.Do(req)
 	if e != nil {
 		return nil, e
 	}
-	req.Body = ioutil.NopCloser(io.MultiReader(buff, file, closeBuffer))
-	req.Header.Add("Content-Type", contentType)
-	req.ContentLength = size + int64(buff.Len()) + int64(closeBuffer.Len())
-	// Making request
-	resp, e := t.client.Do(req)
+	defer resp.Body.Close()
+	d, e := ioutil.ReadAll(resp.Body)
 	if e != nil {
 		return nil, e
 	}
-	defer resp.Body.Close()
-	data, e := ioutil.ReadAll(resp.Body)
-	if e != nil {
+	if e = checkResponce(resp.StatusCode, d); e != nil {
 		return nil, e
 	}
-	return data, nil
+	return d, nil
 }
@@ -0,0 +1,79 @@
+package telebbb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestFormFields Checks that params sent as multipart form fields carry the same values as JSON request
+func TestFormFields(t *testing.T) {
+	markup := &InlineKeyboardMarkup{InlineKeyboard: []*InlineKeyboardButton{{Text: "Yes", CallbackData: "vote:yes"}}}
+	tests := []struct {
+		name   string
+		params interface{}
+	}{
+		{"photo", SendPhotoType{
+			ChatID:              "@channel",
+			Photo:               "AgACAgIAAxkBAAI",
+			Caption:             "Hello, \"world\"\nnew line",
+			ParseMode:           "MarkdownV2",
+			CaptionEntities:     []*MessageEntity{{Type: "bold", Offset: 0, Length: 5}, {Type: "text_link", Offset: 7, Length: 5, URL: "https://telegram.org"}},
+			DisableNotification: true,
+			ReplyToMessageID:    42,
+			ReplyMarkup:         markup,
+		}},
+		{"location", SendLocationType{
+			ChatID:                   -1001234567890,
+			Latitude:                 55.755826,
+			Longitude:                -37.6173,
+			HorizontalAcc:            0.5,
+			LivePeriod:               60,
+			AllowSendingWithoutReply: true,
+			ReplyMarkup:              markup,
+		}},
+		{"empty", SendPhotoType{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, e := json.Marshal(tt.params)
+			if e != nil {
+				t.Fatal(e)
+			}
+			fields, e := formFields(msg)
+			if e != nil {
+				t.Fatal(e)
+			}
+			var params map[string]interface{}
+			if e = json.Unmarshal(msg, &params); e != nil {
+				t.Fatal(e)
+			}
+			for k, v := range params {
+				if v == nil {
+					continue
+				}
+				f, ok := fields[k]
+				if !ok {
+					t.Errorf("field %s is missing", k)
+					continue
+				}
+				// Telegram reads string fields as is and parses other fields as JSON
+				var got interface{} = f
+				if _, isString := v.(string); !isString {
+					if e = json.Unmarshal([]byte(f), &got); e != nil {
+						t.Errorf("field %s = %q is not JSON: %v", k, f, e)
+						continue
+					}
+				}
+				if !reflect.DeepEqual(got, v) {
+					t.Errorf("field %s = %#v, want %#v", k, got, v)
+				}
+			}
+			for k := range fields {
+				if params[k] == nil {
+					t.Errorf("unexpected field %s = %q", k, fields[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package telebbb
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// APICall Describes one telegram api call passed through middleware chain
+type APICall struct {
+	Method string      // Method - telegram method name, for example "sendMessage"
+	Params interface{} // Params - request payload as it was passed to bot method, nil for methods without params
+}
+
+// CallFunc Makes api call and returns raw telegram responce
+type CallFunc func(ctx context.Context, c *APICall) ([]byte, error)
+
+// CallMiddleware Wraps every api call made by bot, middleware can inspect or change call, responce and error,
+// measure latency, or return own responce without calling next, which is handy for test doubles
+type CallMiddleware func(next CallFunc) CallFunc
+
+// invoke Runs api call through middleware chain, first middleware in BotConfig.Middleware is the outermost one
+func (t *TbBot) invoke(ctx context.Context, c *APICall, call CallFunc) ([]byte, error) {
+	for i := len(t.middleware) - 1; i >= 0; i-- {
+		call = t.middleware[i](call)
+	}
+	return call(ctx, c)
+}
+
+// LogCalls Middleware that logs every api call with it's latency and error
+func LogCalls(l *log.Logger) CallMiddleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, c *APICall) ([]byte, error) {
+			start := time.Now()
+			d, e := next(ctx, c)
+			if e != nil {
+				l.Printf("telegram %s failed in %s: %v", c.Method, time.Since(start), e)
+			} else {
+				l.Printf("telegram %s done in %s", c.Method, time.Since(start))
+			}
+			return d, e
+		}
+	}
+}
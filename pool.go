@@ -0,0 +1,105 @@
+package telebbb
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	defaultPoolWorkers = 8   // updates handled at once when PoolConfig.Workers is not set
+	defaultPoolQueue   = 100 // updates waiting for one worker when PoolConfig.QueueSize is not set
+)
+
+// ErrQueueFull Update was dropped because queue of it's worker is full
+var ErrQueueFull = errors.New("update queue is full, update was dropped")
+
+// PoolConfig Concurrent update handling settings for Dispatcher.RunPool, zero values use defaults
+type PoolConfig struct {
+	Workers      int                   // Workers - how many updates are handled at once, default is 8
+	QueueSize    int                   // QueueSize - how many updates can wait for one worker, default is 100
+	DropWhenFull bool                  // DropWhenFull - drop update and report ErrQueueFull if queue is full, by default reading of updates waits
+	Key          func(u *Update) int64 // Key - updates with same key are handled one by one in order they came, default is ChatKey
+}
+
+// ChatKey Keeps updates of one chat ordered, updates without chat are ordered by user
+func ChatKey(u *Update) int64 {
+	if c := u.Chat(); c != nil {
+		return int64(c.ID)
+	}
+	if s := u.Sender(); s != nil {
+		return int64(s.ID)
+	}
+	return int64(u.UpdateID)
+}
+
+// UserKey Keeps updates of one user ordered, updates without user are ordered by chat
+func UserKey(u *Update) int64 {
+	if s := u.Sender(); s != nil {
+		return int64(s.ID)
+	}
+	if c := u.Chat(); c != nil {
+		return int64(c.ID)
+	}
+	return int64(u.UpdateID)
+}
+
+// RunPool Handles updates from bot Incoming channel with several workers until ctx is done or channel is closed
+// Updates with different keys are handled concurrently, updates with same key are handled strictly in order by one worker.
+// When ctx is done queued updates are still passed to handlers with canceled context and RunPool returns after they are handled
+func (d *Dispatcher) RunPool(ctx context.Context, c PoolConfig) error {
+	if c.Workers <= 0 {
+		c.Workers = defaultPoolWorkers
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = defaultPoolQueue
+	}
+	if c.Key == nil {
+		c.Key = ChatKey
+	}
+	queues := make([]chan *Update, c.Workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan *Update, c.QueueSize)
+		wg.Add(1)
+		go func(q chan *Update) { // worker
+			defer wg.Done()
+			for u := range q {
+				if e := d.HandleUpdate(ctx, u); e != nil {
+					d.handleError(u, e)
+				}
+			}
+		}(queues[i])
+	}
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case u, ok := <-d.bot.Incoming:
+			if !ok {
+				return nil
+			}
+			q := queues[uint64(c.Key(u))%uint64(len(queues))]
+			if c.DropWhenFull {
+				select {
+				case q <- u:
+				default:
+					d.handleError(u, ErrQueueFull)
+				}
+				continue
+			}
+			select {
+			case q <- u:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package telebbb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// MethodSpec Describes telegram method which can be called with PostMessage
+type MethodSpec struct {
+	Name   string      // Name - telegram method name, for example "sendMessage"
+	Params interface{} // Params - value of params type method expects, for example SendMessageType{}. Nil accepts any params
+	File   string      // File - name of param which can carry uploaded file, it's sent like in SendPhoto so file cache is used. Empty if method has no files
+	// Call - calls method and returns it's result. If nil, method is called with Call or with File param upload and raw JSON result is returned
+	Call func(ctx context.Context, t *TbBot, message interface{}) (interface{}, error)
+}
+
+var (
+	methodsMu sync.RWMutex
+	methods   = make(map[string]MethodSpec)
+)
+
+// RegisterMethod Adds method to registry used by PostMessage, spec with already registered name replaces old one
+func RegisterMethod(spec MethodSpec) error {
+	if spec.Name == "" {
+		return fmt.Errorf("method name can't be empty")
+	}
+	methodsMu.Lock()
+	defer methodsMu.Unlock()
+	methods[spec.Name] = spec
+	return nil
+}
+
+// LookupMethod Returns registered method spec by telegram method name
+func LookupMethod(name string) (MethodSpec, bool) {
+	methodsMu.RLock()
+	defer methodsMu.RUnlock()
+	spec, ok := methods[name]
+	return spec, ok
+}
+
+// check Returns error if message is not of params type method expects, Params maps are accepted by any method
+func (s MethodSpec) check(message interface{}) error {
+	if s.Params == nil || message == nil {
+		return nil
+	}
+	if _, ok := message.(Params); ok {
+		return nil
+	}
+	if _, ok := message.(map[string]interface{}); ok {
+		return nil
+	}
+	want, got := reflect.TypeOf(s.Params), reflect.TypeOf(message)
+	for got.Kind() == reflect.Ptr {
+		got = got.Elem()
+	}
+	if got != want {
+		return fmt.Errorf("method %s expects %s params, got %s", s.Name, want, got)
+	}
+	return nil
+}
+
+// call Calls method with message
+func (s MethodSpec) call(ctx context.Context, t *TbBot, message interface{}) (interface{}, error) {
+	if s.Call != nil {
+		return s.Call(ctx, t, message)
+	}
+	var r json.RawMessage
+	if s.File != "" {
+		if e := t.callFile(ctx, s.Name, s.File, message, &r); e != nil {
+			return nil, e
+		}
+		return r, nil
+	}
+	if e := t.CallContext(ctx, s.Name, message, &r); e != nil {
+		return nil, e
+	}
+	return r, nil
+}
+
+func init() {
+	for _, spec := range builtinMethods {
+		methods[spec.Name] = spec
+	}
+}
+
+// builtinMethods All methods implemented by library, upload methods take files from *InputFile or *os.File message fields
+var builtinMethods = []MethodSpec{
+	{Name: "getMe", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetMeContext(ctx)
+	}},
+	{Name: "logOut", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.LogOutContext(ctx)
+	}},
+	{Name: "close", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.CloseContext(ctx)
+	}},
+	{Name: "getUpdates", Params: GetUpdatesType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetUpdatesContext(ctx, m)
+	}},
+	{Name: "setWebhook", Params: SetWebhookType{}, File: "certificate", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r bool
+		e := t.callFile(ctx, "setWebhook", "certificate", m, &r)
+		return r, e
+	}},
+	{Name: "deleteWebhook", Params: DeleteWebhookType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.DeleteWebhookContext(ctx, m)
+	}},
+	{Name: "getWebhookInfo", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetWebhookInfoContext(ctx)
+	}},
+	{Name: "sendMessage", Params: SendMessageType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendMessageContext(ctx, m)
+	}},
+	{Name: "forwardMessage", Params: ForwardMessageType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.ForwardMessageContext(ctx, m)
+	}},
+	{Name: "copyMessage", Params: CopyMessageType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.CopyMessageContext(ctx, m)
+	}},
+	{Name: "sendPhoto", Params: SendPhotoType{}, File: "photo", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r Message
+		e := t.callFile(ctx, "sendPhoto", "photo", m, &r)
+		return &r, e
+	}},
+	{Name: "sendAudio", Params: SendAudioType{}, File: "audio", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r Message
+		e := t.callFile(ctx, "sendAudio", "audio", m, &r)
+		return &r, e
+	}},
+	{Name: "sendDocument", Params: SendDocumentType{}, File: "document", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r Message
+		e := t.callFile(ctx, "sendDocument", "document", m, &r)
+		return &r, e
+	}},
+	{Name: "sendVideo", Params: SendVideoType{}, File: "video", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r Message
+		e := t.callFile(ctx, "sendVideo", "video", m, &r)
+		return &r, e
+	}},
+	{Name: "sendAnimation", Params: SendAnimationType{}, File: "animation", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r Message
+		e := t.callFile(ctx, "sendAnimation", "animation", m, &r)
+		return &r, e
+	}},
+	{Name: "sendVoice", Params: SendVoiceType{}, File: "voice", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r Message
+		e := t.callFile(ctx, "sendVoice", "voice", m, &r)
+		return &r, e
+	}},
+	{Name: "sendVideoNote", Params: SendVideoNoteType{}, File: "video_note", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r Message
+		e := t.callFile(ctx, "sendVideoNote", "video_note", m, &r)
+		return &r, e
+	}},
+	{Name: "sendMediaGroup", Params: SendMediaGroupType{}, File: "media", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendMediaGroupContext(ctx, m)
+	}},
+	{Name: "sendLocation", Params: SendLocationType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendLocationContext(ctx, m)
+	}},
+	{Name: "editMessageLiveLocation", Params: EditMessageLiveLocationType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.EditMessageLiveLocationContext(ctx, m)
+	}},
+	{Name: "stopMessageLiveLocation", Params: StopMessageLiveLocationType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.StopMessageLiveLocationContext(ctx, m)
+	}},
+	{Name: "sendVenue", Params: SendVenue{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendVenueContext(ctx, m)
+	}},
+	{Name: "sendContact", Params: SendContactType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendContactContext(ctx, m)
+	}},
+	{Name: "sendPoll", Params: SendPollType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendPollContext(ctx, m)
+	}},
+	{Name: "sendDice", Params: SendDiceType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendDiceContext(ctx, m)
+	}},
+	{Name: "sendChatAction", Params: SendChatActionType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SendChatActionContext(ctx, m)
+	}},
+	{Name: "getUserProfilePhotos", Params: GetUserProfilePhotos{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetUserProfilePhotosContext(ctx, m)
+	}},
+	{Name: "getFile", Params: GetFileType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetFileContext(ctx, m)
+	}},
+	{Name: "kickChatMember", Params: KickChatMemberType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.KickChatMemberContext(ctx, m)
+	}},
+	{Name: "unbanChatMember", Params: UnbanChatMemberType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.UnbanChatMemberContext(ctx, m)
+	}},
+	{Name: "restrictChatMember", Params: RestrictChatMemberType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.RestrictChatMemberContext(ctx, m)
+	}},
+	{Name: "promoteChatMember", Params: PromoteChatMemberType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.PromoteChatMemberContext(ctx, m)
+	}},
+	{Name: "setChatAdministratorCustomTitle", Params: SetChatAdministratorCustomTitleType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SetChatAdministratorCustomTitleContext(ctx, m)
+	}},
+	{Name: "setChatPermissions", Params: SetChatPermissionsType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SetChatPermissionsContext(ctx, m)
+	}},
+	{Name: "exportChatInviteLink", Params: ExportChatInviteLinkType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.ExportChatInviteLinkContext(ctx, m)
+	}},
+	{Name: "createChatInviteLink", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.CreateChatInviteLinkContext(ctx, m)
+	}},
+	{Name: "editChatInviteLink", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.EditChatInviteLinkContext(ctx, m)
+	}},
+	{Name: "revokeChatInviteLink", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.RevokeChatInviteLinkContext(ctx, m)
+	}},
+	{Name: "setChatPhoto", Params: SetChatPhotoType{}, File: "photo", Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		var r bool
+		e := t.callFile(ctx, "setChatPhoto", "photo", m, &r)
+		return r, e
+	}},
+	{Name: "deleteChatPhoto", Params: DeleteChatPhotoType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.DeleteChatPhotoContext(ctx, m)
+	}},
+	{Name: "setChatTitle", Params: SetChatTitleType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SetChatTitleContext(ctx, m)
+	}},
+	{Name: "setChatDescription", Params: SetChatDescriptionType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SetChatDescriptionContext(ctx, m)
+	}},
+	{Name: "pinChatMessage", Params: PinChatMessageType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.PinChatMessageContext(ctx, m)
+	}},
+	{Name: "unpinChatMessage", Params: UnpinChatMessageType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.UnpinChatMessageContext(ctx, m)
+	}},
+	{Name: "unpinAllChatMessages", Params: UnpinAllChatMessagesType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.UnpinAllChatMessagesContext(ctx, m)
+	}},
+	{Name: "leaveChat", Params: LeaveChatType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.LeaveChatContext(ctx, m)
+	}},
+	{Name: "getChat", Params: GetChatType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetChatContext(ctx, m)
+	}},
+	{Name: "getChatAdministrators", Params: GetChatAdministratorsType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetChatAdministratorsContext(ctx, m)
+	}},
+	{Name: "getChatMembersCount", Params: GetChatMembersCountType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetChatMembersCountContext(ctx, m)
+	}},
+	{Name: "getChatMember", Params: GetChatMemberType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.GetChatMemberContext(ctx, m)
+	}},
+	{Name: "setChatStickerSet", Params: SetChatStickerSetType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.SetChatStickerSetContext(ctx, m)
+	}},
+	{Name: "deleteChatStickerSet", Params: DeleteChatStickerSetType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.DeleteChatStickerSetContext(ctx, m)
+	}},
+	{Name: "editMessageText", Params: EditMessageTextType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.EditMessageTextContext(ctx, m)
+	}},
+	{Name: "answerCallbackQuery", Params: AnswerCallbackQueryType{}, Call: func(ctx context.Context, t *TbBot, m interface{}) (interface{}, error) {
+		return t.AnswerCallbackQueryContext(ctx, m)
+	}},
+}
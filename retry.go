@@ -0,0 +1,41 @@
+package telebbb
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3           // retries made when RetryPolicy.MaxRetries is not set
+	defaultMaxWait    = time.Minute // longest retry_after we wait when RetryPolicy.MaxWait is not set
+)
+
+// RetryPolicy Tells how to repeat requests rejected by telegram flood control (429 Too Many Requests)
+// Rejected request was not executed by telegram, so any method including file uploads can be safely repeated
+type RetryPolicy struct {
+	MaxRetries int           // MaxRetries - how many times request is repeated before error is returned, default is 3
+	MaxWait    time.Duration // MaxWait - if telegram asks to wait longer than this, error is returned without waiting, default is 1 minute
+}
+
+// delay Returns how long to wait before repeating request failed with error e, false if request should not be repeated
+func (p *RetryPolicy) delay(e error, attempt int) (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+	var a *APIError
+	if !errors.As(e, &a) || a.RetryAfter() <= 0 {
+		return 0, false
+	}
+	retries, maxWait := p.MaxRetries, p.MaxWait
+	if retries <= 0 {
+		retries = defaultMaxRetries
+	}
+	if maxWait <= 0 {
+		maxWait = defaultMaxWait
+	}
+	wait := time.Duration(a.RetryAfter()) * time.Second
+	if attempt >= retries || wait > maxWait {
+		return 0, false
+	}
+	return wait, true
+}
@@ -1,6 +1,9 @@
 package telebbb
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // BotConfig Main bot configuration
 type BotConfig struct {
@@ -10,16 +13,45 @@ type BotConfig struct {
 		- webhook
 		- local
 	*/
-	Token string // Token - insert your bot token string
-	Port  string // Port - port to listen webhook default is :8000
+	Token          string            // Token - insert your bot token string
+	Port           string            // Port - port to listen webhook default is :8000
+	PollTimeout    int               // PollTimeout - long polling timeout in seconds for local bot type, default is 30
+	AllowedUpdates []string          // AllowedUpdates - list of update types to receive, for example "message", "callback_query". Empty list receives all updates except chat_member
+	WebhookURL     string            // WebhookURL - public HTTPS url of webhook, if set webhook bot type registers it with setWebhook on start
+	WebhookCert    string            // WebhookCert - path to public key certificate to upload with setWebhook, needed only for self-signed certificates
+	WebhookPath    string            // WebhookPath - secret url path webhook accepts updates on, for example "/hook/long-random-string". Empty path accepts any path
+	WebhookSecret  string            // WebhookSecret - secret token telegram sends in X-Telegram-Bot-Api-Secret-Token header, 1-256 characters A-Z, a-z, 0-9, _ and -
+	Retry          *RetryPolicy      // Retry - repeat requests rejected by flood control after retry_after delay, nil disables retries
+	Limits         RateLimits        // Limits - pacing of outgoing messages, by default telegram limits are used
+	Timeout        time.Duration     // Timeout - default timeout of each api request attempt without context deadline, default is 10 seconds. Rate limiter and retry waits are not counted. Uploads and long polling are not limited by it
+	APIURL         string            // APIURL - base url of bot api server, for example "http://localhost:8081" for local Bot API server, default is https://api.telegram.org
+	FileURL        string            // FileURL - base url to download files from, default is APIURL + "/file"
+	Client         *http.Client      // Client - http client to make requests with, use it for proxies or mTLS. Client timeout also limits long polling and uploads, so better leave it empty and use Timeout
+	Transport      http.RoundTripper // Transport - round tripper used instead of Client transport, handy for tracing or test doubles
+	Middleware     []CallMiddleware  // Middleware - chain wrapped around every api call, first one is the outermost
+	Uploads        UploadLimits      // Uploads - size and type checks of uploaded files made before upload, by default telegram limits are used
+	FileCache      FileCache         // FileCache - stores file_id of uploaded files, so same file content is sent by file_id instead of uploading. Nil disables caching
 }
 
 // TbBot Main Bot struct to stor all data, and call bot functions
 type TbBot struct {
-	client   *http.Client
-	token    string
-	Incoming chan interface{}
-	Errors   chan error // Will return error from deep routines to process
+	hookRejected   uint64 // number of rejected webhook requests, accessed with atomic so it's kept first for 64-bit alignment
+	client         *http.Client
+	token          string
+	timeout        time.Duration
+	apiURL         string
+	fileURL        string
+	pollTimeout    int
+	allowedUpdates []string
+	hookPath       string
+	hookSecret     string
+	retry          *RetryPolicy
+	limit          *limiter
+	middleware     []CallMiddleware
+	uploads        UploadLimits
+	fileCache      FileCache
+	Incoming       chan *Update // Will return updates recieved from telegram
+	Errors         chan error   // Will return error from deep routines to process
 }
 
 // ------------------------------
@@ -159,6 +191,8 @@ type Message struct {
 	CaptionEntities        []*MessageEntity `json:"caption_entities,omitempty"`        // Optional. For messages with a caption, special entities like usernames, URLs, bot commands, etc. that appear in the caption
 	Contact                *Contact         `json:"contact,omitempty"`                 // Optional. Message is a shared contact, information about the contact
 	Dice                   *Dice            `json:"dice,omitempty"`                    // Optional. Message is a dice with random value
+	Venue                  *Venue           `json:"venue,omitempty"`                   // Optional. Message is a venue, information about the venue. For backward compatibility, when this field is set, the location field will also be set
+	Location               *Location        `json:"location,omitempty"`                // Optional. Message is a shared location, information about the location
 
 	// TODO
 	// Add all Message fields
@@ -349,6 +383,94 @@ type File struct {
 
 // --------------------------
 
+// --------------------------
+// Update objects
+
+// Update This object represents an incoming update. At most one of the optional parameters can be present in any given update.
+type Update struct {
+	UpdateID           int                 `json:"update_id,omitempty"`            // The update's unique identifier. Update identifiers start from a certain positive number and increase sequentially.
+	Message            *Message            `json:"message,omitempty"`              // Optional. New incoming message of any kind — text, photo, sticker, etc.
+	EditedMessage      *Message            `json:"edited_message,omitempty"`       // Optional. New version of a message that is known to the bot and was edited
+	ChannelPost        *Message            `json:"channel_post,omitempty"`         // Optional. New incoming channel post of any kind — text, photo, sticker, etc.
+	EditedChannelPost  *Message            `json:"edited_channel_post,omitempty"`  // Optional. New version of a channel post that is known to the bot and was edited
+	InlineQuery        *InlineQuery        `json:"inline_query,omitempty"`         // Optional. New incoming inline query
+	ChosenInlineResult *ChosenInlineResult `json:"chosen_inline_result,omitempty"` // Optional. The result of an inline query that was chosen by a user and sent to their chat partner.
+	CallbackQuery      *CallbackQuery      `json:"callback_query,omitempty"`       // Optional. New incoming callback query
+	ShippingQuery      *ShippingQuery      `json:"shipping_query,omitempty"`       // Optional. New incoming shipping query. Only for invoices with flexible price
+	PreCheckoutQuery   *PreCheckoutQuery   `json:"pre_checkout_query,omitempty"`   // Optional. New incoming pre-checkout query. Contains full information about checkout
+	Poll               *Poll               `json:"poll,omitempty"`                 // Optional. New poll state. Bots receive only updates about stopped polls and polls, which are sent by the bot
+	PollAnswer         *PollAnswer         `json:"poll_answer,omitempty"`          // Optional. A user changed their answer in a non-anonymous poll. Bots receive new votes only in polls that were sent by the bot itself.
+	MyChatMember       *ChatMemberUpdated  `json:"my_chat_member,omitempty"`       // Optional. The bot's chat member status was updated in a chat. For private chats, this update is received only when the bot is blocked or unblocked by the user.
+	ChatMember         *ChatMemberUpdated  `json:"chat_member,omitempty"`          // Optional. A chat member's status was updated in a chat. The bot must be an administrator in the chat and must explicitly specify “chat_member” in the list of allowed_updates to receive these updates.
+}
+
+// InlineQuery This object represents an incoming inline query. When the user sends an empty query, your bot could return some default or trending results.
+type InlineQuery struct {
+	ID     string    `json:"id,omitempty"`       // Unique identifier for this query
+	From   *User     `json:"from,omitempty"`     // Sender
+	Loc    *Location `json:"location,omitempty"` // Optional. Sender location, only for bots that request user location
+	Query  string    `json:"query,omitempty"`    // Text of the query (up to 256 characters)
+	Offset string    `json:"offset,omitempty"`   // Offset of the results to be returned, can be controlled by the bot
+}
+
+// ChosenInlineResult Represents a result of an inline query that was chosen by the user and sent to their chat partner.
+type ChosenInlineResult struct {
+	ResultID  string    `json:"result_id,omitempty"`         // The unique identifier for the result that was chosen
+	From      *User     `json:"from,omitempty"`              // The user that chose the result
+	Loc       *Location `json:"location,omitempty"`          // Optional. Sender location, only for bots that require user location
+	InlineMsg string    `json:"inline_message_id,omitempty"` // Optional. Identifier of the sent inline message. Available only if there is an inline keyboard attached to the message. Will be also received in callback queries and can be used to edit the message.
+	Query     string    `json:"query,omitempty"`             // The query that was used to obtain the result
+}
+
+// ShippingAddress This object represents a shipping address.
+type ShippingAddress struct {
+	CountryCode string `json:"country_code,omitempty"` // ISO 3166-1 alpha-2 country code
+	State       string `json:"state,omitempty"`        // State, if applicable
+	City        string `json:"city,omitempty"`         // City
+	StreetLine1 string `json:"street_line1,omitempty"` // First line for the address
+	StreetLine2 string `json:"street_line2,omitempty"` // Second line for the address
+	PostCode    string `json:"post_code,omitempty"`    // Address post code
+}
+
+// OrderInfo This object represents information about an order.
+type OrderInfo struct {
+	Name            string           `json:"name,omitempty"`             // Optional. User name
+	PhoneNumber     string           `json:"phone_number,omitempty"`     // Optional. User's phone number
+	Email           string           `json:"email,omitempty"`            // Optional. User email
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"` // Optional. User shipping address
+}
+
+// ShippingQuery This object contains information about an incoming shipping query.
+type ShippingQuery struct {
+	ID              string           `json:"id,omitempty"`               // Unique query identifier
+	From            *User            `json:"from,omitempty"`             // User who sent the query
+	InvoicePayload  string           `json:"invoice_payload,omitempty"`  // Bot specified invoice payload
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"` // User specified shipping address
+}
+
+// PreCheckoutQuery This object contains information about an incoming pre-checkout query.
+type PreCheckoutQuery struct {
+	ID               string     `json:"id,omitempty"`                 // Unique query identifier
+	From             *User      `json:"from,omitempty"`               // User who sent the query
+	Currency         string     `json:"currency,omitempty"`           // Three-letter ISO 4217 currency code
+	TotalAmount      int        `json:"total_amount,omitempty"`       // Total price in the smallest units of the currency (integer, not float/double). For example, for a price of US$ 1.45 pass amount = 145.
+	InvoicePayload   string     `json:"invoice_payload,omitempty"`    // Bot specified invoice payload
+	ShippingOptionID string     `json:"shipping_option_id,omitempty"` // Optional. Identifier of the shipping option chosen by the user
+	OrderInfo        *OrderInfo `json:"order_info,omitempty"`         // Optional. Order info provided by the user
+}
+
+// ChatMemberUpdated This object represents changes in the status of a chat member.
+type ChatMemberUpdated struct {
+	Chat          *Chat               `json:"chat,omitempty"`            // Chat the user belongs to
+	From          *User               `json:"from,omitempty"`            // Performer of the action, which resulted in the change
+	Date          int                 `json:"date,omitempty"`            // Date the change was done in Unix time
+	OldChatMember *ChatMember         `json:"old_chat_member,omitempty"` // Previous information about the chat member
+	NewChatMember *ChatMember         `json:"new_chat_member,omitempty"` // New information about the chat member
+	InviteLink    *ChatInviteLinkType `json:"invite_link,omitempty"`     // Optional. Chat invite link, which was used by the user to join the chat; for joining by invite link events only.
+}
+
+// --------------------------
+
 // --------------------------
 // Keyboard button types
 
@@ -453,7 +575,7 @@ type ResponseParameters struct {
 // InputMediaPhoto Represents a photo to be sent.
 type InputMediaPhoto struct {
 	Type            string           `json:"type,omitempty"`             // Type of the result, must be photo
-	Media           string           `json:"media,omitempty"`            // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name. https://core.telegram.org/bots/api#sending-files
+	Media           interface{}      `json:"media,omitempty"`            // string or *InputFile. File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name. https://core.telegram.org/bots/api#sending-files
 	Caption         string           `json:"caption,omitempty"`          // Optional. Caption of the photo to be sent, 0-1024 characters after entities parsing
 	ParseMode       string           `json:"parse_mode,omitempty"`       // Optional. Mode for parsing entities in the photo caption. See formatting options for more details. (https://core.telegram.org/bots/api#formatting-options)
 	CaptionEntities []*MessageEntity `json:"caption_entities,omitempty"` // Optional. List of special entities that appear in the caption, which can be specified instead of parse_mode
@@ -462,7 +584,7 @@ type InputMediaPhoto struct {
 // InputMediaVideo Represents a video to be sent.
 type InputMediaVideo struct {
 	Type             string           `json:"type,omitempty"`               // Type of the result, must be video
-	Media            string           `json:"media,omitempty"`              // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name. (https://core.telegram.org/bots/api#sending-files)
+	Media            interface{}      `json:"media,omitempty"`              // string or *InputFile. File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name. (https://core.telegram.org/bots/api#sending-files)
 	Thumb            interface{}      `json:"thumb,omitempty"`              // InputFile(type) or String Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail's width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can't be reused and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was uploaded using multipart/form-data under <file_attach_name>
 	Caption          string           `json:"caption,omitempty"`            // Optional. Caption of the video to be sent, 0-1024 characters after entities parsing
 	ParseMode        string           `json:"parse_mode,omitempty"`         // Optional. Mode for parsing entities in the video caption. See formatting options for more details.
@@ -476,7 +598,7 @@ type InputMediaVideo struct {
 // InputMediaAnimation Represents an animation file (GIF or H.264/MPEG-4 AVC video without sound) to be sent.
 type InputMediaAnimation struct {
 	Type            string           `json:"type,omitempty"`             // Type of the result, must be animation
-	Media           string           `json:"media,omitempty"`            // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name.
+	Media           interface{}      `json:"media,omitempty"`            // string or *InputFile. File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name.
 	Thumb           interface{}      `json:"thumb,omitempty"`            // InputFile(type) or String Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail's width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can't be reused and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was uploaded using multipart/form-data under <file_attach_name>
 	Caption         string           `json:"caption,omitempty"`          // Optional. Caption of the animation to be sent, 0-1024 characters after entities parsing
 	ParseMode       string           `json:"parse_mode,omitempty"`       // Optional. Mode for parsing entities in the animation caption. See formatting options for more details.
@@ -489,7 +611,7 @@ type InputMediaAnimation struct {
 // InputMediaAudio Represents an audio file to be treated as music to be sent.
 type InputMediaAudio struct {
 	Type            string           `json:"type,omitempty"`             // Type of the result, must be audio
-	Media           string           `json:"media,omitempty"`            // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name.
+	Media           interface{}      `json:"media,omitempty"`            // string or *InputFile. File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name.
 	Thumb           interface{}      `json:"thumb,omitempty"`            // InputFile(type) or String Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail's width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can't be reused and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was uploaded using multipart/form-data under <file_attach_name>.
 	Caption         string           `json:"caption,omitempty"`          // Optional. Caption of the audio to be sent, 0-1024 characters after entities parsing
 	ParseMode       string           `json:"parse_mode,omitempty"`       // Optional. Mode for parsing entities in the audio caption. See formatting options for more details.
@@ -502,7 +624,7 @@ type InputMediaAudio struct {
 // InputMediaDocument Represents a general file to be sent.
 type InputMediaDocument struct {
 	Type                        string           `json:"type,omitempty"`                           // Type of the result, must be document
-	Media                       string           `json:"media,omitempty"`                          // File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name
+	Media                       interface{}      `json:"media,omitempty"`                          // string or *InputFile. File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended), pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using multipart/form-data under <file_attach_name> name
 	Thumb                       interface{}      `json:"thumb,omitempty"`                          // InputFile(type) or String Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail's width and height should not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can't be reused and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was uploaded using multipart/form-data under <file_attach_name>.
 	Caption                     string           `json:"caption,omitempty"`                        // Optional. Caption of the document to be sent, 0-1024 characters after entities parsing
 	ParseMode                   string           `json:"parse_mode,omitempty"`                     // Optional. Mode for parsing entities in the document caption. See formatting options for more details.
@@ -811,6 +933,43 @@ type GetUserProfilePhotos struct {
 	Limit  int `json:"limit,omitempty"`   // Optional 	Limits the number of photos to be retrieved. Values between 1-100 are accepted. Defaults to 100.
 }
 
+// GetUpdatesType Use this method to receive incoming updates using long polling. An Array of Update objects is returned.
+type GetUpdatesType struct {
+	Offset         int      `json:"offset,omitempty"`          // Optional. Identifier of the first update to be returned. Must be greater by one than the highest among the identifiers of previously received updates. By default, updates starting with the earliest unconfirmed update are returned. An update is considered confirmed as soon as getUpdates is called with an offset higher than its update_id.
+	Limit          int      `json:"limit,omitempty"`           // Optional. Limits the number of updates to be retrieved. Values between 1-100 are accepted. Defaults to 100.
+	Timeout        int      `json:"timeout,omitempty"`         // Optional. Timeout in seconds for long polling. Defaults to 0, i.e. usual short polling. Should be positive, short polling should be used for testing purposes only.
+	AllowedUpdates []string `json:"allowed_updates,omitempty"` // Optional. A JSON-serialized list of the update types you want your bot to receive. For example, specify [“message”, “edited_channel_post”, “callback_query”] to only receive updates of these types.
+}
+
+// SetWebhookType Use this method to specify a url and receive incoming updates via an outgoing webhook. Whenever there is an update for the bot, we will send an HTTPS POST request to the specified url, containing a JSON-serialized Update. In case of an unsuccessful request, we will give up after a reasonable amount of attempts. Returns True on success.
+type SetWebhookType struct {
+	URL string `json:"url,omitempty"` // HTTPS url to send updates to. Use an empty string to remove webhook integration
+	// Certificate InputFile type
+	Certificate        interface{} `json:"certificate,omitempty"`          // Optional. Upload your public key certificate so that the root certificate in use can be checked. See our self-signed guide for details.
+	IPAddress          string      `json:"ip_address,omitempty"`           // Optional. The fixed IP address which will be used to send webhook requests instead of the IP address resolved through DNS
+	MaxConnections     int         `json:"max_connections,omitempty"`      // Optional. Maximum allowed number of simultaneous HTTPS connections to the webhook for update delivery, 1-100. Defaults to 40. Use lower values to limit the load on your bot's server, and higher values to increase your bot's throughput.
+	AllowedUpdates     []string    `json:"allowed_updates,omitempty"`      // Optional. A JSON-serialized list of the update types you want your bot to receive. For example, specify [“message”, “edited_channel_post”, “callback_query”] to only receive updates of these types.
+	DropPendingUpdates bool        `json:"drop_pending_updates,omitempty"` // Optional. Pass True to drop all pending updates
+	SecretToken        string      `json:"secret_token,omitempty"`         // Optional. A secret token to be sent in a header “X-Telegram-Bot-Api-Secret-Token” in every webhook request, 1-256 characters. Only characters A-Z, a-z, 0-9, _ and - are allowed. The header is useful to ensure that the request comes from a webhook set by you.
+}
+
+// DeleteWebhookType Use this method to remove webhook integration if you decide to switch back to getUpdates. Returns True on success.
+type DeleteWebhookType struct {
+	DropPendingUpdates bool `json:"drop_pending_updates,omitempty"` // Optional. Pass True to drop all pending updates
+}
+
+// WebhookInfo Contains information about the current status of a webhook.
+type WebhookInfo struct {
+	URL                  string   `json:"url,omitempty"`                    // Webhook URL, may be empty if webhook is not set up
+	HasCustomCertificate bool     `json:"has_custom_certificate,omitempty"` // True, if a custom certificate was provided for webhook certificate checks
+	PendingUpdateCount   int      `json:"pending_update_count,omitempty"`   // Number of updates awaiting delivery
+	IPAddress            string   `json:"ip_address,omitempty"`             // Optional. Currently used webhook IP address
+	LastErrorDate        int      `json:"last_error_date,omitempty"`        // Optional. Unix time for the most recent error that happened when trying to deliver an update via webhook
+	LastErrorMessage     string   `json:"last_error_message,omitempty"`     // Optional. Error message in human-readable format for the most recent error that happened when trying to deliver an update via webhook
+	MaxConnections       int      `json:"max_connections,omitempty"`        // Optional. Maximum allowed number of simultaneous HTTPS connections to the webhook for update delivery
+	AllowedUpdates       []string `json:"allowed_updates,omitempty"`        // Optional. A list of update types the bot is subscribed to. Defaults to all update types except chat_member
+}
+
 // GetFileType Use this method to get basic info about a file and prepare it for downloading. For the moment, bots can download files of up to 20MB in size. On success, a File object is returned. The file can then be downloaded via the link https://api.telegram.org/file/bot<token>/<file_path>, where <file_path> is taken from the response. It is guaranteed that the link will be valid for at least 1 hour. When the link expires, a new one can be requested by calling getFile again.
 // Note: This function may not preserve the original file name and MIME type. You should save the file's MIME type and name (if available) when the File object is received.
 type GetFileType struct {
@@ -0,0 +1,185 @@
+package telebbb
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // thumbnails are decoded to check their size
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	defaultMaxUploadSize = 50 << 20  // files bigger than 50 MB are rejected by telegram api server
+	defaultMaxPhotoSize  = 10 << 20  // photos bigger than 10 MB are rejected by telegram
+	maxThumbSize         = 200 << 10 // thumbnail must be at most 200 kB
+	maxThumbSide         = 320       // thumbnail width and height must not exceed 320
+	sniffLen             = 512       // bytes used to detect MIME type, same as http.DetectContentType reads
+)
+
+// mediaTypes MIME types uploaded files are expected to have by method, methods without entry accept any file
+var mediaTypes = map[string][]string{
+	"sendPhoto":     {"image/"},
+	"setChatPhoto":  {"image/"},
+	"sendAudio":     {"audio/", "video/mp4"}, // m4a files are detected as mp4
+	"sendVideo":     {"video/"},
+	"sendAnimation": {"image/gif", "video/mp4"},
+	"sendVoice":     {"application/ogg", "audio/"},
+	"sendVideoNote": {"video/mp4"},
+}
+
+// UploadLimits Checks of uploaded files made before upload starts, zero values use telegram limits
+type UploadLimits struct {
+	Disabled     bool  // Disabled - turns validation off
+	MaxSize      int64 // MaxSize - max size of uploaded file in bytes, default is 50 MB. Local Bot API server accepts files up to 2000 MB
+	MaxPhotoSize int64 // MaxPhotoSize - max size of uploaded photo in bytes, default is 10 MB
+}
+
+// ValidationError Uploaded file breaks telegram limits, file was not sent
+type ValidationError struct {
+	Method string // Method - telegram method name
+	Param  string // Param - param name file was sent as
+	File   string // File - name of file
+	Reason string // Reason - what is wrong with file
+}
+
+// Error Returns error message
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("can't upload %s as %s of %s: %s", v.File, v.Param, v.Method, v.Reason)
+}
+
+// validate Checks size and MIME type of files before upload
+func (l *UploadLimits) validate(method string, files []formFile) error {
+	if l.Disabled {
+		return nil
+	}
+	maxSize, maxPhoto := l.MaxSize, l.MaxPhotoSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxUploadSize
+	}
+	if maxPhoto <= 0 {
+		maxPhoto = defaultMaxPhotoSize
+	}
+	for _, f := range files {
+		fail := func(reason string, a ...interface{}) error {
+			return &ValidationError{Method: method, Param: f.name, File: f.file.name, Reason: fmt.Sprintf(reason, a...)}
+		}
+		if strings.HasPrefix(f.name, "thumb") {
+			if e := validateThumb(f.file); e != "" {
+				return fail(e)
+			}
+			continue
+		}
+		head, e := f.file.head(sniffLen)
+		if e != nil {
+			return e
+		}
+		mime := http.DetectContentType(head)
+		if types, ok := mediaTypes[method]; ok && !hasPrefix(mime, types) && mime != "application/octet-stream" {
+			return fail("file type %s is not one of %s", mime, strings.Join(types, ", "))
+		}
+		limit := maxSize
+		isPhoto := method == "sendPhoto" || method == "setChatPhoto" || (method == "sendMediaGroup" && strings.HasPrefix(mime, "image/"))
+		if isPhoto && maxPhoto < limit {
+			limit = maxPhoto
+		}
+		if size := f.file.size(); size > limit {
+			return fail("file size %d is bigger than %d bytes", size, limit)
+		}
+	}
+	return nil
+}
+
+// validateThumb Returns why thumbnail can't be sent, empty string if it's valid
+func validateThumb(f *InputFile) string {
+	head, e := f.head(maxThumbSize + 1)
+	if e != nil {
+		return e.Error()
+	}
+	if len(head) > maxThumbSize {
+		return fmt.Sprintf("thumbnail must be at most %d bytes", maxThumbSize)
+	}
+	c, format, e := image.DecodeConfig(bytes.NewReader(head))
+	if e != nil || format != "jpeg" {
+		return "thumbnail must be in JPEG format"
+	}
+	if c.Width > maxThumbSide || c.Height > maxThumbSide {
+		return fmt.Sprintf("thumbnail is %dx%d, width and height must not exceed %d", c.Width, c.Height, maxThumbSide)
+	}
+	return ""
+}
+
+// hasPrefix Reports if s starts with any of prefixes
+func hasPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// head Returns first n bytes of file content without consuming them
+// Readers which can't seek are wrapped, so read bytes are sent first during upload
+func (f *InputFile) head(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if f.path != "" {
+		r, e := os.Open(f.path)
+		if e != nil {
+			return nil, e
+		}
+		defer r.Close()
+		k, e := io.ReadFull(r, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return nil, e
+		}
+		return buf[:k], nil
+	}
+	if f.reader == nil {
+		return nil, fmt.Errorf("input file %s has no content", f.name)
+	}
+	k, e := io.ReadFull(f.reader, buf)
+	if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+		return nil, e
+	}
+	buf = buf[:k]
+	// Pipes and sockets are *os.File too, but seek fails on them, so they are wrapped like any other stream
+	if s, ok := f.reader.(io.Seeker); ok {
+		if _, e = s.Seek(int64(-k), io.SeekCurrent); e == nil {
+			return buf, nil
+		}
+	}
+	f.reader = io.MultiReader(bytes.NewReader(buf), f.reader)
+	return buf, nil
+}
+
+// size Returns size of file content or -1 if it's unknown
+func (f *InputFile) size() int64 {
+	if f.path != "" {
+		fi, e := os.Stat(f.path)
+		if e != nil {
+			return -1
+		}
+		return fi.Size()
+	}
+	switch r := f.reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case io.Seeker:
+		pos, e := r.Seek(0, io.SeekCurrent)
+		if e != nil {
+			return -1
+		}
+		end, e := r.Seek(0, io.SeekEnd)
+		if e != nil {
+			return -1
+		}
+		if _, e = r.Seek(pos, io.SeekStart); e != nil {
+			return -1
+		}
+		return end - pos
+	}
+	return -1
+}
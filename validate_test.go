@@ -0,0 +1,49 @@
+package telebbb
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// TestUploadPipe Checks that file which can't seek, like pipe, is validated and uploaded as stream and closed after upload
+func TestUploadPipe(t *testing.T) {
+	const content = "streamed document content"
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, _, e := r.FormFile("document")
+		if e != nil {
+			http.Error(w, `{"ok":false,"error_code":400,"description":"Bad Request: no document"}`, http.StatusBadRequest)
+			return
+		}
+		d, _ := ioutil.ReadAll(f)
+		got = string(d)
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer srv.Close()
+
+	b, e := NewBot(BotConfig{Type: "none", Token: "test", APIURL: srv.URL})
+	if e != nil {
+		t.Fatal(e)
+	}
+	pr, pw, e := os.Pipe()
+	if e != nil {
+		t.Fatal(e)
+	}
+	go func() {
+		pw.Write([]byte(content))
+		pw.Close()
+	}()
+	if _, e = b.SendDocument(SendDocumentType{ChatID: 1}, FileReader("a.txt", pr)); e != nil {
+		t.Fatal(e)
+	}
+	if got != content {
+		t.Errorf("uploaded %q, want %q", got, content)
+	}
+	if _, e = pr.Read(make([]byte, 1)); !errors.Is(e, os.ErrClosed) {
+		t.Errorf("pipe is not closed after upload, read returned %v", e)
+	}
+}
@@ -1,22 +1,109 @@
 package telebbb
 
-import "net/http"
-
-// ServeHook Starts http listener for telegram server
-func (s *TbBot) ServeHook(port string) {
-	http.HandleFunc("/", s.hook)
-	if port != "" {
-		if err := http.ListenAndServe(port, nil); err != nil {
-			panic(err)
-		}
-	} else {
-		if err := http.ListenAndServe(":8000", nil); err != nil {
-			panic(err)
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maxHookBody         = 1 << 20         // biggest update body we accept from telegram server
+	hookDeliveryTimeout = time.Second * 5 // how long webhook waits for free place in Incoming before asking telegram to redeliver
+
+	// SecretTokenHeader Header telegram uses to send secret token set with setWebhook
+	SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+)
+
+// ServeHook Starts http listener for telegram server, default port is :8000
+// Listener uses own http.Server so the default mux is not touched, returns error if server can't be started
+// If you already have http server mount TbBot as http.Handler instead
+func (s *TbBot) ServeHook(port string) error {
+	return s.ServeHookContext(context.Background(), port)
+}
+
+// ServeHookContext Same as ServeHook, but gracefully shuts down server when ctx is done
+// Requests contexts are derived from ctx, so handlers see cancelation too
+func (s *TbBot) ServeHookContext(ctx context.Context, port string) error {
+	if port == "" {
+		port = ":8000"
+	}
+	srv := &http.Server{
+		Addr:        port,
+		Handler:     s,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	done := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop: // server failed to start or stopped by itself
+			return
 		}
+		sctx, cancel := context.WithTimeout(context.Background(), hookDeliveryTimeout)
+		defer cancel()
+		done <- srv.Shutdown(sctx)
+	}()
+	if e := srv.ListenAndServe(); e != http.ErrServerClosed {
+		return e
+	}
+	if e := <-done; e != nil {
+		return e
 	}
+	return ctx.Err()
+}
+
+// ServeHTTP Recieves update posted by telegram server and sends it to Incoming channel
+// Telegram gets answer as soon as update is queued, if Incoming stays full we answer with 503 and telegram will redeliver update later
+// If BotConfig.WebhookPath or BotConfig.WebhookSecret are set requests with other path or secret token are rejected
+func (s *TbBot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.hookPath != "" && r.URL.Path != s.hookPath {
+		s.rejectHook(r, "unknown path %q", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+	if s.hookSecret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretTokenHeader)), []byte(s.hookSecret)) != 1 {
+		s.rejectHook(r, "invalid secret token")
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var u Update
+	if e := json.NewDecoder(io.LimitReader(r.Body, maxHookBody)).Decode(&u); e != nil {
+		s.reportError(fmt.Errorf("can't decode webhook update: %w", e))
+		http.Error(w, "invalid update", http.StatusBadRequest)
+		return
+	}
+	t := time.NewTimer(hookDeliveryTimeout)
+	defer t.Stop()
+	select {
+	case s.Incoming <- &u:
+		w.WriteHeader(http.StatusOK)
+	case <-t.C:
+		http.Error(w, "updates queue is full", http.StatusServiceUnavailable)
+	case <-r.Context().Done(): // connection is probably gone, but answer anyway so update is redelivered
+		http.Error(w, "request canceled", http.StatusServiceUnavailable)
+	}
+}
+
+// RejectedHooks Returns number of webhook requests rejected because of wrong path or secret token
+func (s *TbBot) RejectedHooks() uint64 {
+	return atomic.LoadUint64(&s.hookRejected)
 }
 
-func (s *TbBot) hook(w http.ResponseWriter, r *http.Request) {
-	// TODO
-	// Recieve message from telegram server
+// rejectHook Counts rejected webhook request and reports it to Errors channel
+func (s *TbBot) rejectHook(r *http.Request, format string, a ...interface{}) {
+	atomic.AddUint64(&s.hookRejected, 1)
+	s.reportError(fmt.Errorf("rejected webhook request from %s: %s", r.RemoteAddr, fmt.Sprintf(format, a...)))
 }